@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"terraform-cloud-s3-migrator/internal/migrator"
+
+	"github.com/spf13/cobra"
+)
+
+// Anotação que declara como o comando trata a flag global --dry-run. Comandos novos
+// herdam a flag e só precisam declarar o modo suportado.
+const (
+	dryRunAnnotation = "dry-run"
+	dryRunSupported  = "supported" // O comando simula as alterações sem executá-las
+	dryRunReadOnly   = "read-only" // O comando não altera nada; a flag é aceita sem efeito
+)
+
+// checkDryRun recusa --dry-run em comandos que alteram dados e ainda não sabem simular
+// as alterações, para que a flag nunca seja ignorada silenciosamente
+func checkDryRun(cmd *cobra.Command, args []string) error {
+	if !dryRun {
+		return nil
+	}
+
+	switch cmd.Annotations[dryRunAnnotation] {
+	case dryRunSupported, dryRunReadOnly:
+		return nil
+	default:
+		return fmt.Errorf("o comando %s não suporta --dry-run", cmd.Name())
+	}
+}
+
+// commandOptions retorna as opções comuns a todos os comandos: --dry-run e --projects
+func commandOptions() migrator.MigrationOptions {
+	return migrator.MigrationOptions{
+		DryRun:   dryRun,
+		Projects: parseProjectList(projects),
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCheckDryRun(t *testing.T) {
+	defer func(previous bool) { dryRun = previous }(dryRun)
+	dryRun = true
+
+	tests := []struct {
+		cmd     *cobra.Command
+		wantErr bool
+	}{
+		{cmd: migrateCmd},
+		{cmd: doctorCmd},
+		{cmd: bootstrapCmd, wantErr: true},
+		{cmd: exportCmd, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cmd.Name(), func(t *testing.T) {
+			err := checkDryRun(tt.cmd, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkDryRun(%s) = %v, esperado erro: %v", tt.cmd.Name(), err, tt.wantErr)
+			}
+		})
+	}
+}
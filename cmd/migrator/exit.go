@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"terraform-cloud-s3-migrator/internal/migrator"
+)
+
+// Códigos de saída documentados para uso em CI
+const (
+	exitSuccess         = 0 // Todos os workspaces migrados
+	exitPartialFailure  = 1 // Falhas em parte dos workspaces (ou erro não classificado)
+	exitConfigError     = 2 // Configuração inválida ou ausente
+	exitConnectionError = 3 // Falha ao conectar ao Terraform Cloud ou ao S3
+	exitNothingToDo     = 4 // Nenhum workspace a migrar
+	exitTimeout         = 5 // Prazo de --timeout esgotado antes do fim da execução
+)
+
+// exitError associa um erro ao código de saída do processo.
+// Com err nil, o processo termina com o código sem exibir mensagem de erro.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("encerrado com código %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode associa o código de saída ao erro
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// exitCode retorna o código de saída correspondente ao erro retornado pelos comandos
+func exitCode(err error) int {
+	if err == nil {
+		return exitSuccess
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	var connErr *migrator.ConnectionError
+	if errors.As(err, &connErr) {
+		return exitConnectionError
+	}
+
+	return exitPartialFailure
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"terraform-cloud-s3-migrator/internal/migrator"
+)
+
+func TestExitCode(t *testing.T) {
+	connErr := &migrator.ConnectionError{Err: errors.New("token inválido")}
+	migrationErr := &migrator.MigrationError{Failures: []migrator.FailedMigration{{WorkspaceName: "network"}}}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "sucesso", err: nil, want: exitSuccess},
+		{name: "erro de configuração", err: withExitCode(exitConfigError, errors.New("aws.bucket é obrigatório")), want: exitConfigError},
+		{name: "erro de configuração encapsulado", err: fmt.Errorf("migrate: %w", withExitCode(exitConfigError, errors.New("flag inválida"))), want: exitConfigError},
+		{name: "erro de conexão", err: connErr, want: exitConnectionError},
+		{name: "erro de conexão encapsulado", err: fmt.Errorf("erro ao listar workspaces: %w", connErr), want: exitConnectionError},
+		{name: "código explícito prevalece sobre a conexão", err: withExitCode(exitConfigError, connErr), want: exitConfigError},
+		{name: "nada a fazer", err: withExitCode(exitNothingToDo, nil), want: exitNothingToDo},
+		{name: "falha parcial", err: migrationErr, want: exitPartialFailure},
+		{name: "prazo esgotado", err: withExitCode(exitTimeout, migrationErr), want: exitTimeout},
+		{name: "erro não classificado", err: errors.New("falha inesperada"), want: exitPartialFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, esperado %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitErrorMessage(t *testing.T) {
+	if got := withExitCode(exitNothingToDo, nil).Error(); got != "encerrado com código 4" {
+		t.Errorf("mensagem sem erro = %q", got)
+	}
+
+	cause := errors.New("aws.bucket é obrigatório")
+	err := withExitCode(exitConfigError, cause)
+	if err.Error() != cause.Error() {
+		t.Errorf("mensagem = %q, esperado %q", err.Error(), cause.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Error("withExitCode deve preservar o erro original")
+	}
+}
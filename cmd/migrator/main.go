@@ -1,24 +1,94 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"terraform-cloud-s3-migrator/internal/config"
+	"terraform-cloud-s3-migrator/internal/metrics"
 	"terraform-cloud-s3-migrator/internal/migrator"
+	"terraform-cloud-s3-migrator/internal/terraform"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+// highConcurrency é o valor de concurrent_uploads a partir do qual o Terraform Cloud costuma limitar as requisições
+const highConcurrency = 50
+
+// Formatos de saída aceitos pela flag --output
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var (
+	cfgFile      string
+	batchSize    int
+	concurrency  int
+	retries      int
+	s3Bucket     string
+	s3Prefix     string
+	maxFailures  int
+	failFast     bool
+	noState      bool
+	showDiff     bool
+	dryRun       bool
+	force        bool
+	reportFile   string
+	progressFile string
+	projects     string
+	projectsFile string
+	exclude      string
+	useRegex     bool
+	tags         string
+	tfcProject   string
+	since        string
+	timeout      time.Duration
+	skipRecent   time.Duration
+	skipLocked   bool
+	onlyLocked   bool
+	output       string
+	listLimit    int
+	listPage     int
+	detailed     bool
+	lockEntries  bool
+	history      bool
+	variables    bool
+	strict       bool
+	skipValidate bool
+	rewriteBknd  bool
+	requireVers  bool
+	overwrite    bool
+	assumeYes    bool
+	pushgateway  string
+	outputDir    string
+	logLevel     string
+	quiet        bool
+	verbose      bool
+	appVersion   string = "dev" // Será definida durante o build
+)
+
+// Flags do comando init
 var (
-	cfgFile    string
-	batchSize  int
-	dryRun     bool
-	projects   string
-	logLevel   string
-	appVersion string = "dev" // Será definida durante o build
+	initToken        string
+	initOrganization string
+	initBucket       string
+	initRegion       string
+	initPrefix       string
+	tokenFromEnv     bool
 )
 
 var rootCmd = &cobra.Command{
@@ -26,6 +96,7 @@ var rootCmd = &cobra.Command{
 	Short: "Terraform Cloud to S3 State Migrator",
 	Long: `Uma ferramenta para migrar estados do Terraform Cloud para o Amazon S3
 com controle de batch processing e configuração flexível.`,
+	PersistentPreRunE: persistentPreRun,
 }
 
 var versionCmd = &cobra.Command{
@@ -44,8 +115,27 @@ Mostra quais workspaces têm estado (migráveis) e quais não têm (serão ignor
 
 Exemplos:
   migrator list                    # Lista todos os workspaces
+  migrator list --output json      # Lista em JSON (para uso com jq)
+  migrator list --limit 50 --page 2  # Lista apenas a segunda página de 50 workspaces
   migrator list --log-level debug  # Lista com logs detalhados`,
-	RunE: runList,
+	RunE:        runList,
+	Annotations: map[string]string{dryRunAnnotation: dryRunReadOnly},
+}
+
+var listS3Cmd = &cobra.Command{
+	Use:   "list-s3",
+	Short: "Lista os estados já migrados no bucket S3",
+	Long: `Lista os estados gravados sob o prefixo configurado (e nos buckets de
+aws.environment_buckets), lendo o metadata.json de cada workspace.
+
+Mostra o workspace, o serial, a data da migração e o tamanho do estado, sem
+acessar o Terraform Cloud.
+
+Exemplos:
+  migrator list-s3                 # Lista os estados migrados
+  migrator list-s3 --output json   # Lista em JSON (para uso com jq)`,
+	RunE:        runListS3,
+	Annotations: map[string]string{dryRunAnnotation: dryRunReadOnly},
 }
 
 var migrateCmd = &cobra.Command{
@@ -56,18 +146,206 @@ var migrateCmd = &cobra.Command{
 Opções de migração:
   • TODOS os workspaces: Execute sem especificar projetos
   • Projetos específicos: Use a flag --projects com lista separada por vírgula
+  • Exclusões: Use a flag --exclude para ignorar workspaces específicos
+  • Padrões: --projects e --exclude aceitam globs (ex: "payments-*") ou, com --regex,
+    expressões regulares que devem casar com o nome inteiro do workspace
   • Simulação: Use --dry-run para testar sem fazer alterações
 
 Workspaces sem estado do Terraform são automaticamente ignorados.
-Workspaces já migrados anteriormente são pulados.
+Workspaces já migrados anteriormente são pulados, a menos que --overwrite seja usado
+e o serial do estado no Terraform Cloud seja diferente do registrado no S3.
+Workspaces registrados no checkpoint são pulados, a menos que --force seja usado.
 
 Exemplos:
   migrator migrate                                    # Migra TODOS os workspaces
   migrator migrate --dry-run                          # Simula a migração
+  migrator migrate --yes                              # Migra sem pedir confirmação
   migrator migrate --projects \"prod,staging\"           # Migra projetos específicos
-  migrator migrate --batch-size 10                    # Ajusta tamanho do batch
+  migrator migrate --projects-file wave1.txt          # Migra os workspaces listados no arquivo
+  migrator migrate --batch-size 10                    # Loga o andamento a cada 10
+  migrator migrate --concurrent-uploads 8             # Migra 8 workspaces em paralelo
+  migrator migrate --bucket scratch --prefix test/    # Migra para outro bucket/prefixo
+  migrator migrate --exclude \"legacy,sandbox\"        # Migra todos exceto os listados
+  migrator migrate --projects \"payments-*\"           # Migra workspaces por glob
+  migrator migrate --tags \"team:payments\"            # Migra workspaces com as tags
+  migrator migrate --tfc-project \"Networking\"        # Migra workspaces de um projeto do Terraform Cloud
+  migrator migrate --projects \"app-.*\" --regex       # Migra workspaces por regex
+  migrator migrate --include-history                  # Migra também o histórico de estados
+  migrator migrate --include-variables                # Grava também as variáveis do workspace
+  migrator migrate --projects \"app1\" --strict       # Falha se app1 não existir ou não tiver estado
+  migrator migrate --force                            # Ignora o checkpoint
+  migrator migrate --since 7d                         # Migra apenas estados alterados nos últimos 7 dias
+  migrator migrate --skip-recent 1h                   # Pula estados alterados na última hora
+  migrator migrate --skip-locked                      # Pula workspaces travados (apply em andamento)
+  migrator migrate --max-failures 5                   # Aborta após mais de 5 falhas
+  migrator migrate --require-versioning               # Falha se o bucket não tiver versionamento
+  migrator migrate --report report.json               # Grava relatório em JSON
+  migrator migrate --progress-file progress.json      # Atualiza as estatísticas parciais a cada 10s
+  migrator migrate --metrics-pushgateway http://pushgateway:9091  # Envia métricas ao Prometheus
   migrator migrate --projects \"app1,app2\" --dry-run   # Simula migração específica`,
-	RunE: runMigrate,
+	RunE:        runMigrate,
+	Annotations: map[string]string{dryRunAnnotation: dryRunSupported},
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Remove do S3 os estados enviados pelo migrator",
+	Long: `Remove do Amazon S3 os estados (terraform.tfstate e metadata.json) enviados
+anteriormente pelo comando migrate.
+
+Somente objetos cujo metadata.json indica origem "terraform_cloud" são removidos,
+garantindo que estados pré-existentes no bucket nunca sejam afetados.
+
+Exemplos:
+  migrator rollback --dry-run                         # Mostra o que seria removido
+  migrator rollback                                   # Remove TODOS os estados migrados
+  migrator rollback --projects \"app1,app2\"           # Remove apenas projetos específicos`,
+	RunE:        runRollback,
+	Annotations: map[string]string{dryRunAnnotation: dryRunSupported},
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verifica se os estados no S3 são idênticos aos do Terraform Cloud",
+	Long: `Compara o hash SHA-256 do estado atual de cada workspace no Terraform Cloud
+com o estado enviado ao Amazon S3.
+
+Workspaces com estado no Terraform Cloud mas sem objeto no S3 são reportados
+como "NOT MIGRATED". O comando retorna erro se alguma divergência for encontrada.
+
+Com --diff, cada divergência é detalhada em nível de recurso: serial, lineage,
+quantidade de recursos e endereços adicionados ou removidos.
+
+Exemplos:
+  migrator verify                                     # Verifica TODOS os workspaces
+  migrator verify --projects \"app1,app2\"             # Verifica projetos específicos
+  migrator verify --diff                              # Detalha as divergências`,
+	RunE:        runVerify,
+	Annotations: map[string]string{dryRunAnnotation: dryRunReadOnly},
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Mostra quais workspaces já foram migrados e quais estão pendentes",
+	Long: `Lista todos os workspaces da organização e verifica, para cada um com estado,
+se o estado já existe no Amazon S3 (usando o nome limpo, sem sufixo de ambiente).
+
+Os workspaces são agrupados em:
+  • Migrados: estado já existe no S3
+  • Pendentes: possuem estado no Terraform Cloud, mas ainda não estão no S3
+  • Ignorados: não possuem estado
+
+Exemplos:
+  migrator status                                     # Resumo em texto
+  migrator status --output json                       # Resumo em JSON (para uso com jq)`,
+	RunE:        runStatus,
+	Annotations: map[string]string{dryRunAnnotation: dryRunReadOnly},
+}
+
+var bootstrapCmd = &cobra.Command{
+	Use:   "bootstrap",
+	Short: "Cria o bucket S3 de destino com as proteções recomendadas",
+	Long: `Cria o bucket configurado em aws.bucket na região aws.region com:
+  • Versionamento habilitado
+  • Bloqueio de todo acesso público
+  • Criptografia padrão (SSE-KMS com aws.kms_key_id, ou SSE-S3)
+
+Se o bucket já existir, nada é alterado. O comando falha se o bucket pertencer a
+outra conta (ou a uma conta diferente de aws.accountid, quando configurado).
+
+Exemplos:
+  migrator bootstrap                                  # Cria o bucket se necessário`,
+	RunE: runBootstrap,
+}
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Atualiza no S3 apenas os estados desatualizados em relação ao Terraform Cloud",
+	Long: `Lista os estados migrados no Amazon S3, lê o serial registrado em cada metadata.json
+e compara com o serial atual do workspace no Terraform Cloud.
+
+Cada workspace com estado é reportado como:
+  • up-to-date: o S3 já possui o serial atual
+  • stale in S3 (will update): o serial no Terraform Cloud é maior e o estado será reenviado
+  • missing: o estado ainda não foi migrado (use o comando migrate)
+
+Exemplos:
+  migrator reconcile --dry-run                        # Apenas mostra a comparação
+  migrator reconcile                                  # Reenvia os estados desatualizados
+  migrator reconcile --projects "app1,app2"          # Reconcilia projetos específicos
+  migrator reconcile --dry-run --output json          # Comparação em JSON`,
+	RunE:        runReconcile,
+	Annotations: map[string]string{dryRunAnnotation: dryRunSupported},
+}
+
+var generateBackendCmd = &cobra.Command{
+	Use:   "generate-backend",
+	Short: "Gera blocos backend \"s3\" para os workspaces migrados",
+	Long: `Gera a configuração backend "s3" (bucket, key, region e, se configurados,
+dynamodb_table e kms_key_id) para cada workspace já migrado para o S3.
+
+Sem --output-dir os blocos são impressos no stdout. Com --output-dir é gravado
+um arquivo <workspace>.tf por workspace.
+
+Exemplos:
+  migrator generate-backend                           # Imprime todos os backends
+  migrator generate-backend --output-dir ./backends   # Grava um arquivo por workspace
+  migrator generate-backend --projects \"app1,app2\"   # Apenas projetos específicos`,
+	RunE: runGenerateBackend,
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exporta os estados do Terraform Cloud para um diretório local",
+	Long: `Baixa o estado atual e os metadados de cada workspace e os grava em --output-dir,
+usando o mesmo layout de chaves do S3 (aws.prefix, aws.key_template e nomes de arquivo).
+
+Nenhuma conexão com a AWS é feita e aws.bucket não é obrigatório, o que permite
+inspecionar os estados ou enviá-los depois com outra ferramenta (ex: aws s3 sync).
+Os arquivos são gravados com permissão 0600, pois estados podem conter segredos.
+
+Exemplos:
+  migrator export --output-dir ./states               # Exporta todos os workspaces
+  migrator export --output-dir ./states --projects \"app1,app2\"`,
+	RunE: runExport,
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Verifica todos os pré-requisitos antes de uma migração",
+	Long: `Executa as verificações de pré-requisitos e imprime uma lista com o resultado de cada uma:
+  • Configuração completa e válida
+  • Token e organização do Terraform Cloud
+  • Acesso a cada bucket de destino, na conta (aws.accountid) e região esperadas
+  • Versionamento e criptografia padrão habilitados
+  • Permissões de escrita, leitura e remoção, gravando e removendo o objeto _healthcheck
+
+O comando termina com código diferente de zero se alguma verificação falhar.
+Com --dry-run nada é gravado no bucket e a verificação de escrita é pulada.
+
+Exemplos:
+  migrator doctor                                     # Verifica a configuração padrão
+  migrator doctor --dry-run                           # Sem gravar o objeto _healthcheck
+  migrator doctor --config ./config/prod.yaml         # Verifica outra configuração`,
+	Annotations: map[string]string{dryRunAnnotation: dryRunSupported},
+	RunE:        runDoctor,
+}
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Cria um arquivo config.yaml inicial",
+	Long: `Cria o arquivo de configuração (config.yaml ou o caminho em --config) com o token,
+a organização do Terraform Cloud e o bucket, a região e o prefixo do S3.
+
+Os valores não informados por flags são perguntados interativamente. O token pode
+ser mantido fora do arquivo, na variável de ambiente TFC_TOKEN.
+Um arquivo existente só é sobrescrito com --force.
+
+Exemplos:
+  migrator init                                       # Pergunta todos os valores
+  migrator init --organization acme --bucket states --token-from-env
+  migrator init --config ./config/config.yaml --force # Sobrescreve um arquivo existente`,
+	RunE: runInit,
 }
 
 func init() {
@@ -76,15 +354,106 @@ func init() {
 	// Flags globais
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "arquivo de configuração (padrão é config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "nível de log (debug, info, warn, error)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "exibe apenas erros (equivale a --log-level error)")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "exibe logs detalhados (equivale a --log-level debug)")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "simula a execução sem alterar nada (migrate, rollback, reconcile); sem efeito em comandos somente leitura")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "tempo máximo de execução do comando (ex: 30m); ao esgotar, o trabalho em andamento é cancelado e o processo termina com código 5")
+
+	// Flags para o comando list
+	listCmd.Flags().StringVar(&output, "output", outputText, "formato de saída (text, json)")
+	listCmd.Flags().StringVar(&s3Bucket, "bucket", "", "bucket S3 de destino, sobrescreve aws.bucket")
+	listCmd.Flags().StringVar(&s3Prefix, "prefix", "", "prefixo das chaves no bucket, sobrescreve aws.prefix")
+	listCmd.Flags().IntVar(&listLimit, "limit", 0, "lista apenas uma página com até N workspaces (máximo 100)")
+	listCmd.Flags().IntVar(&listPage, "page", 1, "página a listar com --limit, a partir de 1")
+	listCmd.Flags().BoolVar(&detailed, "detailed", false, "mostra a quantidade de recursos de cada workspace")
+
+	// Flags para o comando list-s3
+	listS3Cmd.Flags().StringVar(&output, "output", outputText, "formato de saída (text, json)")
+	listS3Cmd.Flags().StringVar(&s3Bucket, "bucket", "", "bucket S3 a listar, sobrescreve aws.bucket")
+	listS3Cmd.Flags().StringVar(&s3Prefix, "prefix", "", "prefixo das chaves no bucket, sobrescreve aws.prefix")
 
 	// Flags para o comando migrate
-	migrateCmd.Flags().IntVar(&batchSize, "batch-size", 0, "número de projetos a processar por vez")
-	migrateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "apenas simula a migração sem executá-la")
+	migrateCmd.Flags().IntVar(&batchSize, "batch-size", 0, "a cada quantos workspaces processados registrar o andamento no log")
+	migrateCmd.Flags().IntVar(&concurrency, "concurrent-uploads", 0, "número de workspaces migrados em paralelo, sobrescreve migration.concurrent_uploads")
+	migrateCmd.Flags().IntVar(&retries, "retry-attempts", 0, "tentativas por workspace em caso de falha, sobrescreve migration.retry_attempts")
+	migrateCmd.Flags().StringVar(&s3Bucket, "bucket", "", "bucket S3 de destino, sobrescreve aws.bucket")
+	migrateCmd.Flags().StringVar(&s3Prefix, "prefix", "", "prefixo das chaves no bucket, sobrescreve aws.prefix")
+	migrateCmd.Flags().StringVar(&output, "output", outputText, "formato do resumo final no stdout (text, json; json não imprime o resumo, use --report)")
+	migrateCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "não pede confirmação antes da migração")
+	migrateCmd.Flags().IntVar(&maxFailures, "max-failures", 0, "aborta a execução quando o número de falhas ultrapassar N (migration.max_failures)")
+	migrateCmd.Flags().BoolVar(&noState, "include-no-state", false, "grava apenas o metadata.json (has_state: false) dos workspaces sem estado")
+	migrateCmd.Flags().BoolVar(&failFast, "fail-fast", false, "aborta a execução no primeiro erro de autenticação ou permissão (HTTP 401/403)")
 	migrateCmd.Flags().StringVar(&projects, "projects", "", "lista de projetos específicos para migrar (separados por vírgula)")
+	migrateCmd.Flags().StringVar(&projectsFile, "projects-file", "", "arquivo com um workspace por linha (linhas vazias e comentários com # são ignorados)")
+	migrateCmd.Flags().StringVar(&exclude, "exclude", "", "lista de workspaces a ignorar na migração (separados por vírgula)")
+	migrateCmd.Flags().StringVar(&tags, "tags", "", "migra apenas workspaces com todas as tags informadas (separadas por vírgula)")
+	migrateCmd.Flags().StringVar(&tfcProject, "tfc-project", "", "migra apenas workspaces do projeto do Terraform Cloud com este nome (combinável com --projects)")
+	migrateCmd.Flags().BoolVar(&useRegex, "regex", false, "interpreta --projects e --exclude como expressões regulares em vez de globs")
+	migrateCmd.Flags().StringVar(&since, "since", "", "migra apenas workspaces cujo estado atual foi criado após a data (ex: 7d, 12h, 2024-05-01)")
+	migrateCmd.Flags().DurationVar(&skipRecent, "skip-recent", 0, "pula workspaces cujo estado atual foi criado dentro da janela informada, por estarem em uso (ex: 1h, 30m)")
+	migrateCmd.Flags().BoolVar(&skipLocked, "skip-locked", false, "pula workspaces travados, que podem estar no meio de um apply")
+	migrateCmd.Flags().BoolVar(&onlyLocked, "only-locked", false, "migra apenas workspaces travados (ex: workspaces congelados deliberadamente)")
+	migrateCmd.MarkFlagsMutuallyExclusive("skip-locked", "only-locked")
+	migrateCmd.Flags().BoolVar(&strict, "strict", false, "falha se algum workspace informado pelo nome em --projects não existir ou não tiver estado")
+	migrateCmd.Flags().BoolVar(&skipValidate, "skip-validation", false, "não valida se o conteúdo baixado é um estado do Terraform antes do upload")
+	migrateCmd.Flags().BoolVar(&rewriteBknd, "rewrite-backend", false, "remove do estado o bloco backend que aponta para o Terraform Cloud (remote/cloud) antes do upload")
+	migrateCmd.Flags().BoolVar(&requireVers, "require-versioning", false, "falha se o versionamento do bucket S3 não estiver habilitado")
+	migrateCmd.Flags().BoolVar(&overwrite, "overwrite", false, "reenvia estados já existentes no S3 cujo serial mudou (migration.overwrite)")
+	migrateCmd.Flags().BoolVar(&force, "force", false, "reprocessa workspaces já registrados no checkpoint")
+	migrateCmd.Flags().BoolVar(&lockEntries, "create-lock-entries", false, "grava o digest de cada estado na tabela DynamoDB (aws.dynamodb_table)")
+	migrateCmd.Flags().BoolVar(&history, "include-history", false, "migra também todas as versões anteriores do estado (migration.include_history)")
+	migrateCmd.Flags().BoolVar(&variables, "include-variables", false, "grava as variáveis do workspace em variables.json, sem valores sensíveis (migration.include_variables)")
+	migrateCmd.Flags().StringVar(&pushgateway, "metrics-pushgateway", "", "URL do Prometheus Pushgateway para enviar as métricas da execução")
+	migrateCmd.Flags().StringVar(&reportFile, "report", "", "arquivo para gravar o relatório da migração em JSON")
+	migrateCmd.Flags().StringVar(&progressFile, "progress-file", "", "arquivo reescrito periodicamente com as estatísticas parciais em JSON, para monitoramento")
+
+	// Flags para o comando init
+	initCmd.Flags().StringVar(&initToken, "tfc-token", "", "token do Terraform Cloud a gravar no arquivo")
+	initCmd.Flags().BoolVar(&tokenFromEnv, "token-from-env", false, "não grava o token no arquivo (use a variável TFC_TOKEN)")
+	initCmd.Flags().StringVar(&initOrganization, "organization", "", "organização do Terraform Cloud")
+	initCmd.Flags().StringVar(&initBucket, "bucket", "", "bucket S3 de destino")
+	initCmd.Flags().StringVar(&initRegion, "region", "", "região AWS do bucket (padrão us-east-1)")
+	initCmd.Flags().StringVar(&initPrefix, "prefix", "", "prefixo das chaves no bucket (padrão terraform-states/)")
+	initCmd.Flags().BoolVar(&force, "force", false, "sobrescreve o arquivo de configuração se ele já existir")
+	initCmd.MarkFlagsMutuallyExclusive("tfc-token", "token-from-env")
+
+	// Flags para o comando rollback
+	rollbackCmd.Flags().StringVar(&projects, "projects", "", "lista de projetos específicos para remover (separados por vírgula)")
+
+	// Flags para o comando verify
+	verifyCmd.Flags().StringVar(&projects, "projects", "", "lista de projetos específicos para verificar (separados por vírgula)")
+	verifyCmd.Flags().BoolVar(&showDiff, "diff", false, "mostra serial, lineage e recursos adicionados/removidos dos estados divergentes")
+
+	// Flags para o comando status
+	statusCmd.Flags().StringVar(&output, "output", outputText, "formato de saída (text, json)")
+
+	// Flags para o comando reconcile
+	reconcileCmd.Flags().StringVar(&projects, "projects", "", "lista de projetos específicos para reconciliar (separados por vírgula)")
+	reconcileCmd.Flags().StringVar(&output, "output", outputText, "formato de saída (text, json)")
+
+	// Flags para o comando generate-backend
+	generateBackendCmd.Flags().StringVar(&outputDir, "output-dir", "", "diretório onde gravar um arquivo .tf por workspace")
+	generateBackendCmd.Flags().StringVar(&projects, "projects", "", "lista de projetos específicos (separados por vírgula)")
+
+	// Flags para o comando export
+	exportCmd.Flags().StringVar(&outputDir, "output-dir", "", "diretório onde gravar os estados e metadados")
+	exportCmd.Flags().StringVar(&projects, "projects", "", "lista de projetos específicos para exportar (separados por vírgula)")
+	exportCmd.MarkFlagRequired("output-dir")
 
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(bootstrapCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(listS3Cmd)
 	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(reconcileCmd)
+	rootCmd.AddCommand(generateBackendCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(doctorCmd)
 }
 
 func initConfig() {
@@ -96,19 +465,48 @@ func initConfig() {
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadConfig()
+	if err := validateOutput(output); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(cfgFile)
 	if err != nil {
 		return fmt.Errorf("erro ao carregar configuração: %w", err)
 	}
 
-	setupLogging(cfg)
+	if err := setupLogging(cfg); err != nil {
+		return err
+	}
+
+	// Override de configurações via flags
+	applyS3Overrides(cfg)
+
+	if cmd.Flags().Changed("page") && listLimit == 0 {
+		return fmt.Errorf("--page requer --limit")
+	}
+	if listLimit < 0 || listLimit > terraform.MaxPageSize {
+		return fmt.Errorf("--limit deve estar entre 1 e %d", terraform.MaxPageSize)
+	}
+	if listPage < 1 {
+		return fmt.Errorf("--page deve ser maior ou igual a 1")
+	}
 
 	m, err := migrator.NewMigrator(cfg)
 	if err != nil {
 		return fmt.Errorf("erro ao criar migrator: %w", err)
 	}
 
-	workspaces, err := m.ListWorkspaces()
+	// Com --limit apenas a página solicitada é obtida, sem percorrer a organização inteira
+	var workspaces []terraform.Workspace
+	var page *terraform.WorkspacePage
+	if listLimit > 0 {
+		page, err = m.ListWorkspacesPage(cmd.Context(), listPage, listLimit)
+		if err == nil {
+			workspaces = page.Workspaces
+		}
+	} else {
+		workspaces, err = m.ListWorkspaces(cmd.Context())
+	}
 	if err != nil {
 		return fmt.Errorf("erro ao listar workspaces: %w", err)
 	}
@@ -123,7 +521,52 @@ func runList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("\n Workspaces encontrados na organização '%s':\n\n", cfg.TerraformCloud.Organization)
+	if output == outputJSON {
+		result := map[string]interface{}{
+			"organization": cfg.TerraformCloud.Organization,
+			"workspaces":   workspaces,
+			"summary": map[string]int{
+				"total":         len(workspaces),
+				"with_state":    withState,
+				"without_state": withoutState,
+			},
+		}
+		if page != nil {
+			result["pagination"] = map[string]int{
+				"page":        page.Page,
+				"total_pages": page.TotalPages,
+				"total_count": page.TotalCount,
+			}
+		}
+		return printJSON(result)
+	}
+
+	// Com logs em JSON a listagem é registrada pelo logger em vez de impressa
+	if cfg.Logging.Format == config.LogFormatJSON {
+		for _, ws := range workspaces {
+			logrus.WithFields(logrus.Fields{
+				"workspace":     ws.Name,
+				"id":            ws.ID,
+				"has_state":     ws.HasState,
+				"tags":          ws.Tags,
+				"tfc_project":   ws.ProjectName,
+				"state_version": ws.CurrentStateVersion,
+				"resources":     ws.ResourceCount,
+			}).Info("Workspace encontrado")
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"organization":  cfg.TerraformCloud.Organization,
+			"total":         len(workspaces),
+			"with_state":    withState,
+			"without_state": withoutState,
+		}).Info("Resumo dos workspaces")
+		return nil
+	}
+
+	if !quiet {
+		fmt.Printf("\n Workspaces encontrados na organização '%s':\n\n", cfg.TerraformCloud.Organization)
+	}
 
 	for i, ws := range workspaces {
 		stateIcon := "❌"
@@ -138,9 +581,30 @@ func runList(cmd *cobra.Command, args []string) error {
 			fmt.Printf("Descrição: %s\n", ws.Description)
 		}
 		fmt.Printf("  ID: %s\n", ws.ID)
+		if len(ws.Tags) > 0 {
+			fmt.Printf("  Tags: %s\n", strings.Join(ws.Tags, ", "))
+		}
+		if ws.ProjectName != "" {
+			fmt.Printf("  Projeto TFC: %s\n", ws.ProjectName)
+		}
 		if ws.HasState {
 			fmt.Printf(" Versão do estado: %s\n", ws.CurrentStateVersion)
 		}
+		if detailed {
+			fmt.Printf("  Recursos: %d\n", ws.ResourceCount)
+		}
+		fmt.Println()
+	}
+
+	if quiet {
+		return nil
+	}
+
+	if page != nil {
+		fmt.Printf(" Página %d de %d (%d workspaces na organização)\n", page.Page, page.TotalPages, page.TotalCount)
+		if page.Page < page.TotalPages {
+			fmt.Printf("   Próxima página: ./migrator list --limit %d --page %d\n", listLimit, page.Page+1)
+		}
 		fmt.Println()
 	}
 
@@ -162,40 +626,136 @@ func runList(cmd *cobra.Command, args []string) error {
 }
 
 func runMigrate(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadConfig()
+	// Erros de execução são exibidos por Execute, com o código de saída correspondente
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	if err := validateOutput(output); err != nil {
+		return withExitCode(exitConfigError, err)
+	}
+
+	cfg, err := config.LoadConfig(cfgFile)
 	if err != nil {
-		return fmt.Errorf("erro ao carregar configuração: %w", err)
+		return withExitCode(exitConfigError, fmt.Errorf("erro ao carregar configuração: %w", err))
 	}
 
-	setupLogging(cfg)
+	if err := setupLogging(cfg); err != nil {
+		return withExitCode(exitConfigError, err)
+	}
+
+	if reportFile != "" {
+		path, err := config.ExpandPath(reportFile)
+		if err != nil {
+			return withExitCode(exitConfigError, err)
+		}
+		reportFile = path
+	}
+
+	if progressFile != "" {
+		path, err := config.ExpandPath(progressFile)
+		if err != nil {
+			return withExitCode(exitConfigError, err)
+		}
+		progressFile = path
+	}
 
 	// Override de configurações via flags
 	if batchSize > 0 {
 		cfg.Migration.BatchSize = batchSize
 	}
+	if maxFailures > 0 {
+		cfg.Migration.MaxFailures = maxFailures
+	}
+	if cmd.Flags().Changed("concurrent-uploads") {
+		if concurrency < 1 {
+			return withExitCode(exitConfigError, fmt.Errorf("--concurrent-uploads deve ser maior ou igual a 1"))
+		}
+		cfg.Migration.ConcurrentUploads = concurrency
+	}
+	if cmd.Flags().Changed("retry-attempts") {
+		if retries < 1 {
+			return withExitCode(exitConfigError, fmt.Errorf("--retry-attempts deve ser maior ou igual a 1"))
+		}
+		cfg.Migration.RetryAttempts = retries
+	}
+	applyS3Overrides(cfg)
 
-	if logLevel != "" {
-		cfg.Logging.Level = logLevel
+	if cfg.Migration.ConcurrentUploads > highConcurrency {
+		logrus.WithField("concurrent_uploads", cfg.Migration.ConcurrentUploads).Warnf("Concorrência acima de %d costuma causar limitação de requisições (HTTP 429) no Terraform Cloud", highConcurrency)
 	}
 
 	m, err := migrator.NewMigrator(cfg)
 	if err != nil {
-		return fmt.Errorf("erro ao criar migrator: %w", err)
+		return withExitCode(exitConfigError, fmt.Errorf("erro ao criar migrator: %w", err))
 	}
 
 	// Preparar lista de projetos específicos
-	var projectList []string
-	if projects != "" {
-		projectList = strings.Split(projects, ",")
-		for i, p := range projectList {
-			projectList[i] = strings.TrimSpace(p)
+	projectList := parseProjectList(projects)
+	if projectsFile != "" {
+		fileProjects, err := readProjectsFile(projectsFile)
+		if err != nil {
+			return withExitCode(exitConfigError, err)
 		}
+		projectList = append(projectList, fileProjects...)
+	}
+	if len(projectList) > 0 {
 		logrus.WithField("projects", projectList).Info("Projetos específicos selecionados para migração")
 	}
 
+	excludeList := parseProjectList(exclude)
+	if len(excludeList) > 0 {
+		logrus.WithField("exclude", excludeList).Info("Workspaces que serão ignorados na migração")
+	}
+
+	var sinceCutoff time.Time
+	if since != "" {
+		sinceCutoff, err = parseSince(since, time.Now())
+		if err != nil {
+			return withExitCode(exitConfigError, err)
+		}
+		logrus.WithField("since", sinceCutoff.Format(time.RFC3339)).Info("Selecionando apenas estados criados após a data de corte")
+	}
+
+	var recentCutoff time.Time
+	if skipRecent < 0 {
+		return withExitCode(exitConfigError, fmt.Errorf("valor inválido para --skip-recent: %s (use uma duração positiva como 1h)", skipRecent))
+	}
+	if skipRecent > 0 {
+		recentCutoff = time.Now().Add(-skipRecent)
+		logrus.WithField("skip_recent", recentCutoff.Format(time.RFC3339)).Info("Ignorando estados criados após a data de corte")
+	}
+
 	options := migrator.MigrationOptions{
-		DryRun:   dryRun,
-		Projects: projectList,
+		DryRun:     dryRun,
+		Projects:   projectList,
+		Exclude:    excludeList,
+		Regex:      useRegex,
+		Tags:       parseProjectList(tags),
+		TFCProject: tfcProject,
+		Force:      force,
+		Strict:     strict,
+		Overwrite:  overwrite || cfg.Migration.Overwrite,
+
+		CreateLockEntries: lockEntries,
+		IncludeHistory:    history || cfg.Migration.IncludeHistory,
+		IncludeVariables:  variables || cfg.Migration.IncludeVariables,
+		SkipValidation:    skipValidate,
+		RewriteBackend:    rewriteBknd,
+		RequireVersioning: requireVers,
+		MaxFailures:       cfg.Migration.MaxFailures,
+		FailFast:          failFast,
+		IncludeNoState:    noState,
+		Since:             sinceCutoff,
+		RecentCutoff:      recentCutoff,
+		SkipLocked:        skipLocked,
+		OnlyLocked:        onlyLocked,
+		Progress:          progressWriter(cfg),
+		ProgressFile:      progressFile,
+	}
+
+	// Sem terminal não é possível perguntar, então a confirmação só é pedida em sessões interativas
+	if !dryRun && !assumeYes && isTerminal(os.Stdin) {
+		options.Confirm = confirmMigration(cfg)
 	}
 
 	if dryRun {
@@ -210,15 +770,648 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 		"organization":       cfg.TerraformCloud.Organization,
 	}).Info("Iniciando migração")
 
-	if err := m.Migrate(options); err != nil {
+	// SIGINT/SIGTERM interrompe o agendamento de novos workspaces; um segundo sinal encerra o processo
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	stats, err := m.Migrate(ctx, options)
+
+	// O relatório é gravado mesmo em caso de falhas parciais
+	if reportFile != "" && stats != nil {
+		if reportErr := stats.WriteReport(reportFile); reportErr != nil {
+			logrus.WithError(reportErr).Error("Erro ao gravar relatório da migração")
+		} else {
+			logrus.WithField("report", reportFile).Info("Relatório da migração gravado")
+		}
+	}
+
+	if stats != nil {
+		printMigrationSummary(stats, dryRun)
+	}
+
+	if pushgateway != "" && stats != nil {
+		if pushErr := pushMetrics(cfg, stats); pushErr != nil {
+			logrus.WithError(pushErr).Error("Erro ao enviar métricas ao Pushgateway")
+		} else {
+			logrus.WithField("pushgateway", pushgateway).Info("Métricas enviadas ao Pushgateway")
+		}
+	}
+
+	if err != nil {
 		return fmt.Errorf("erro durante a migração: %w", err)
 	}
 
+	if stats.Total == 0 {
+		logrus.Info(" Nenhum workspace a migrar")
+		return withExitCode(exitNothingToDo, nil)
+	}
+
 	logrus.Info(" Migração concluída com sucesso!")
 	return nil
 }
 
-func setupLogging(cfg *config.Config) {
+func runRollback(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("erro ao carregar configuração: %w", err)
+	}
+
+	if err := setupLogging(cfg); err != nil {
+		return err
+	}
+
+	m, err := migrator.NewMigrator(cfg)
+	if err != nil {
+		return fmt.Errorf("erro ao criar migrator: %w", err)
+	}
+
+	projectList := parseProjectList(projects)
+	if len(projectList) > 0 {
+		logrus.WithField("projects", projectList).Info("Projetos específicos selecionados para rollback")
+	}
+
+	if dryRun {
+		logrus.Info("MODO DRY-RUN ativado - nenhum objeto será removido")
+	}
+
+	options := commandOptions()
+
+	if err := m.Rollback(cmd.Context(), options); err != nil {
+		return fmt.Errorf("erro durante o rollback: %w", err)
+	}
+
+	logrus.Info(" Rollback concluído com sucesso!")
+	return nil
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("erro ao carregar configuração: %w", err)
+	}
+
+	if err := setupLogging(cfg); err != nil {
+		return err
+	}
+
+	m, err := migrator.NewMigrator(cfg)
+	if err != nil {
+		return fmt.Errorf("erro ao criar migrator: %w", err)
+	}
+
+	options := commandOptions()
+	options.Diff = showDiff
+
+	results, err := m.Verify(cmd.Context(), options)
+	if err != nil {
+		return fmt.Errorf("erro durante a verificação: %w", err)
+	}
+
+	var passed, failed, notMigrated int
+
+	fmt.Printf("\n Verificação dos estados na organização '%s':\n\n", cfg.TerraformCloud.Organization)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WORKSPACE\tS3\tSTATUS\tDETALHE")
+	for _, result := range results {
+		switch result.Status {
+		case migrator.VerifyPass:
+			passed++
+		case migrator.VerifyNotMigrated:
+			notMigrated++
+		default:
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.WorkspaceName, result.S3Name, result.Status, result.Error)
+	}
+	w.Flush()
+
+	for _, result := range results {
+		if result.Diff != nil {
+			printStateDiff(result.WorkspaceName, result.Diff)
+		}
+	}
+
+	fmt.Printf("\n Resumo:\n")
+	fmt.Printf("   • Total verificado: %d\n", len(results))
+	fmt.Printf("   • Idênticos (PASS): %d\n", passed)
+	fmt.Printf("   • Divergentes (FAIL): %d\n", failed)
+	fmt.Printf("   • Não migrados: %d\n", notMigrated)
+
+	if failed > 0 {
+		return fmt.Errorf("verificação encontrou %d divergências", failed)
+	}
+
+	return nil
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	if err := validateOutput(output); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("erro ao carregar configuração: %w", err)
+	}
+
+	if err := setupLogging(cfg); err != nil {
+		return err
+	}
+
+	m, err := migrator.NewMigrator(cfg)
+	if err != nil {
+		return fmt.Errorf("erro ao criar migrator: %w", err)
+	}
+
+	report, err := m.Status(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("erro ao verificar status: %w", err)
+	}
+
+	if output == outputJSON {
+		return printJSON(report)
+	}
+
+	fmt.Printf("\n Status da migração na organização '%s':\n", cfg.TerraformCloud.Organization)
+
+	printStatusGroup("✅ Migrados", report.Migrated)
+	printStatusGroup("⏳ Pendentes", report.Pending)
+	printStatusGroup("➖ Ignorados (sem estado)", report.Skipped)
+
+	if len(report.Errors) > 0 {
+		fmt.Printf("\n ❌ Erros na verificação (%d):\n", len(report.Errors))
+		for _, failed := range report.Errors {
+			fmt.Printf("   • %s: %s\n", failed.WorkspaceName, failed.Error)
+		}
+	}
+
+	fmt.Printf("\n Resumo:\n")
+	fmt.Printf("   • Migrados: %d\n", len(report.Migrated))
+	fmt.Printf("   • Pendentes: %d\n", len(report.Pending))
+	fmt.Printf("   • Ignorados: %d\n", len(report.Skipped))
+
+	return nil
+}
+
+// printStatusGroup imprime um grupo de workspaces do comando status
+func printStatusGroup(title string, workspaces []string) {
+	fmt.Printf("\n %s (%d):\n", title, len(workspaces))
+	for _, name := range workspaces {
+		fmt.Printf("   • %s\n", name)
+	}
+}
+
+func runListS3(cmd *cobra.Command, args []string) error {
+	if err := validateOutput(output); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("erro ao carregar configuração: %w", err)
+	}
+
+	if err := setupLogging(cfg); err != nil {
+		return err
+	}
+
+	applyS3Overrides(cfg)
+
+	states, err := migrator.ListMigratedStates(cmd.Context(), cfg)
+	if err != nil {
+		return fmt.Errorf("erro ao listar estados no S3: %w", err)
+	}
+
+	if output == outputJSON {
+		return printJSON(states)
+	}
+
+	fmt.Printf("\n Estados migrados da organização '%s' (%d):\n\n", cfg.TerraformCloud.Organization, len(states))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WORKSPACE\tBUCKET\tSERIAL\tMIGRADO EM\tBYTES")
+	for _, state := range states {
+		serial, size := "-", "-"
+		if state.HasState {
+			serial = fmt.Sprintf("%d", state.Serial)
+			size = fmt.Sprintf("%d", state.Size)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", state.WorkspaceName, state.Bucket, serial,
+			state.MigratedAt.Local().Format("2006-01-02 15:04:05"), size)
+	}
+	w.Flush()
+
+	return nil
+}
+
+func runBootstrap(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("erro ao carregar configuração: %w", err)
+	}
+
+	if err := setupLogging(cfg); err != nil {
+		return err
+	}
+
+	created, err := migrator.Bootstrap(cmd.Context(), cfg)
+	if err != nil {
+		return fmt.Errorf("erro ao preparar bucket: %w", err)
+	}
+
+	if created {
+		logrus.WithFields(logrus.Fields{
+			"bucket": cfg.AWS.Bucket,
+			"region": cfg.AWS.Region,
+		}).Info(" Bucket criado com versionamento, acesso público bloqueado e criptografia padrão")
+	}
+
+	return nil
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cfgFile)
+	if err != nil {
+		printDoctorChecks([]migrator.DoctorCheck{{Name: "Configuração", Error: err.Error()}})
+		return withExitCode(exitConfigError, fmt.Errorf("erro ao carregar configuração: %w", err))
+	}
+
+	if err := setupLogging(cfg); err != nil {
+		return err
+	}
+
+	checks := []migrator.DoctorCheck{{Name: "Configuração", Detail: config.GetConfigPath()}}
+	checks = append(checks, migrator.Doctor(cmd.Context(), cfg, dryRun)...)
+	printDoctorChecks(checks)
+
+	failed := 0
+	for _, check := range checks {
+		if !check.Passed() {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d de %d verificações falharam", failed, len(checks))
+	}
+
+	logrus.Info(" Todos os pré-requisitos da migração foram atendidos")
+	return nil
+}
+
+// printDoctorChecks imprime a lista de verificações do comando doctor
+func printDoctorChecks(checks []migrator.DoctorCheck) {
+	fmt.Printf("\n Verificações da migração:\n\n")
+	for _, check := range checks {
+		line := check.Name
+		if check.Detail != "" {
+			line += fmt.Sprintf(" (%s)", check.Detail)
+		}
+
+		if check.Passed() {
+			fmt.Printf("   ✅ %s\n", line)
+		} else {
+			fmt.Printf("   ❌ %s: %s\n", line, check.Error)
+		}
+	}
+	fmt.Println()
+}
+
+func runReconcile(cmd *cobra.Command, args []string) error {
+	if err := validateOutput(output); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("erro ao carregar configuração: %w", err)
+	}
+
+	if err := setupLogging(cfg); err != nil {
+		return err
+	}
+
+	m, err := migrator.NewMigrator(cfg)
+	if err != nil {
+		return fmt.Errorf("erro ao criar migrator: %w", err)
+	}
+
+	results, err := m.Reconcile(cmd.Context(), commandOptions())
+	if err != nil {
+		return fmt.Errorf("erro durante a reconciliação: %w", err)
+	}
+
+	var stale []string
+	var upToDate, missing, failed int
+	for _, result := range results {
+		switch result.Status {
+		case migrator.ReconcileStale:
+			stale = append(stale, result.WorkspaceName)
+		case migrator.ReconcileUpToDate:
+			upToDate++
+		case migrator.ReconcileMissing:
+			missing++
+		default:
+			failed++
+		}
+	}
+
+	if output == outputJSON {
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("\n Reconciliação dos estados na organização '%s':\n\n", cfg.TerraformCloud.Organization)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "WORKSPACE\tS3\tSERIAL TFC\tSERIAL S3\tSTATUS\tDETALHE")
+		for _, result := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", result.WorkspaceName, result.S3Name,
+				formatSerial(result.SourceSerial), formatSerial(result.TargetSerial), result.Status, result.Error)
+		}
+		w.Flush()
+
+		fmt.Printf("\n Resumo:\n")
+		fmt.Printf("   • Atualizados: %d\n", upToDate)
+		fmt.Printf("   • Desatualizados no S3: %d\n", len(stale))
+		fmt.Printf("   • Não migrados: %d\n", missing)
+		fmt.Printf("   • Erros: %d\n", failed)
+	}
+
+	if dryRun || len(stale) == 0 {
+		return nil
+	}
+
+	logrus.WithField("workspaces", stale).Info("Reenviando estados desatualizados no S3")
+
+	// SIGINT/SIGTERM interrompe o agendamento de novos workspaces; um segundo sinal encerra o processo
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if _, err := m.Migrate(ctx, migrator.MigrationOptions{
+		Projects:         stale,
+		Overwrite:        true,
+		IncludeHistory:   cfg.Migration.IncludeHistory,
+		IncludeVariables: cfg.Migration.IncludeVariables,
+		Progress:         progressWriter(cfg),
+	}); err != nil {
+		return fmt.Errorf("erro ao atualizar estados desatualizados: %w", err)
+	}
+
+	logrus.Info(" Reconciliação concluída com sucesso!")
+	return nil
+}
+
+// formatSerial formata um serial para exibição, usando "-" quando desconhecido
+func formatSerial(serial int64) string {
+	if serial < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", serial)
+}
+
+func runGenerateBackend(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("erro ao carregar configuração: %w", err)
+	}
+
+	if err := setupLogging(cfg); err != nil {
+		return err
+	}
+
+	m, err := migrator.NewMigrator(cfg)
+	if err != nil {
+		return fmt.Errorf("erro ao criar migrator: %w", err)
+	}
+
+	backends, err := m.GenerateBackends(cmd.Context(), commandOptions())
+	if err != nil {
+		return fmt.Errorf("erro ao gerar backends: %w", err)
+	}
+
+	if outputDir != "" {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("erro ao criar diretório %s: %w", outputDir, err)
+		}
+	}
+
+	for _, backend := range backends {
+		content, err := backend.Render()
+		if err != nil {
+			return err
+		}
+
+		if outputDir == "" {
+			fmt.Println(content)
+			continue
+		}
+
+		path := filepath.Join(outputDir, backend.WorkspaceName+".tf")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("erro ao gravar %s: %w", path, err)
+		}
+		logrus.WithField("file", path).Info("Backend gerado")
+	}
+
+	if outputDir != "" {
+		fmt.Printf("\n %d arquivos de backend gravados em %s\n", len(backends), outputDir)
+	}
+
+	return nil
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	// O export não acessa o S3, por isso o bucket não é obrigatório
+	cfg, err := config.LoadLocalConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("erro ao carregar configuração: %w", err)
+	}
+
+	if err := setupLogging(cfg); err != nil {
+		return err
+	}
+
+	stats, err := migrator.Export(cmd.Context(), cfg, commandOptions(), outputDir)
+	if stats != nil {
+		fmt.Printf("\n %d estados exportados para %s (%d falhas)\n", stats.Exported, outputDir, len(stats.Failed))
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao exportar estados: %w", err)
+	}
+
+	return nil
+}
+
+// pushMetrics envia ao Pushgateway as métricas da execução
+func pushMetrics(cfg *config.Config, stats *migrator.MigrationStats) error {
+	run := metrics.Run{
+		Organization: cfg.TerraformCloud.Organization,
+		Successful:   stats.Successful,
+		Failed:       stats.Failed,
+	}
+	for _, result := range stats.WorkspaceResults {
+		run.Durations = append(run.Durations, result.Duration)
+		run.Bytes += result.Bytes
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return metrics.Push(ctx, pushgateway, run)
+}
+
+// validateOutput valida o valor da flag --output
+func validateOutput(value string) error {
+	if value != outputText && value != outputJSON {
+		return fmt.Errorf("formato de saída inválido '%s' (use %s ou %s)", value, outputText, outputJSON)
+	}
+	return nil
+}
+
+// printJSON imprime o valor em JSON no stdout
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("erro ao serializar saída JSON: %w", err)
+	}
+	return nil
+}
+
+// parseProjectList converte a lista de projetos separada por vírgula em slice
+func parseProjectList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	projectList := strings.Split(value, ",")
+	for i, p := range projectList {
+		projectList[i] = strings.TrimSpace(p)
+	}
+
+	return projectList
+}
+
+// confirmMigration retorna a confirmação interativa da migração, que exige a resposta "yes"
+func confirmMigration(cfg *config.Config) func(total int) (bool, error) {
+	return func(total int) (bool, error) {
+		fmt.Fprintf(os.Stderr, "\nSerão migrados %d workspaces da organização '%s' para o bucket '%s'.\n",
+			total, cfg.TerraformCloud.Organization, cfg.AWS.Bucket)
+		fmt.Fprint(os.Stderr, "Digite 'yes' para continuar: ")
+
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, err
+		}
+
+		return strings.TrimSpace(answer) == "yes", nil
+	}
+}
+
+// isTerminal indica se o arquivo é um terminal interativo
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// applyS3Overrides aplica as flags --bucket e --prefix sobre a configuração
+func applyS3Overrides(cfg *config.Config) {
+	if s3Bucket != "" {
+		cfg.AWS.Bucket = s3Bucket
+	}
+	if s3Prefix != "" {
+		cfg.AWS.Prefix = s3Prefix
+	}
+}
+
+// parseSince converte o valor de --since em uma data de corte: uma duração relativa a now,
+// com suporte a dias (ex: 7d, 12h, 30m), ou uma data absoluta (2006-01-02 ou RFC 3339)
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("valor inválido para --since: %s (use uma duração como 7d ou 12h, ou uma data como 2024-05-01)", value)
+}
+
+// progressWriter retorna o destino da linha de progresso, ou nil quando ela deve ser suprimida
+func progressWriter(cfg *config.Config) io.Writer {
+	if !showProgress(cfg, isTerminal(os.Stderr)) {
+		return nil
+	}
+	return os.Stderr
+}
+
+// showProgress indica se a linha de progresso pode ser escrita no stderr. Ela só é exibida quando
+// os logs vão para logging.file, para não se misturar a eles no stderr, e nunca com saída ou logs
+// em JSON nem em stderr redirecionado, que não devem receber caracteres de controle.
+func showProgress(cfg *config.Config, terminal bool) bool {
+	if quiet || output == outputJSON || cfg.Logging.Format == config.LogFormatJSON {
+		return false
+	}
+	return cfg.Logging.File != "" && terminal
+}
+
+// readProjectsFile lê a lista de workspaces do arquivo, um por linha, ignorando linhas vazias e comentários.
+// Um arquivo sem nenhum workspace é um erro.
+func readProjectsFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de projetos %s: %w", path, err)
+	}
+
+	var projectList []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		projectList = append(projectList, line)
+	}
+
+	// Um arquivo vazio selecionaria todos os workspaces da organização
+	if len(projectList) == 0 {
+		return nil, fmt.Errorf("arquivo de projetos %s não contém nenhum workspace", path)
+	}
+
+	return projectList, nil
+}
+
+// setupLogging configura nível, formato e destino dos logs. Os diretórios de logging.file
+// são criados se não existirem.
+func setupLogging(cfg *config.Config) error {
+	// Flags têm precedência sobre o nível configurado: --quiet/--verbose, depois --log-level
+	switch {
+	case quiet:
+		cfg.Logging.Level = "error"
+	case verbose:
+		cfg.Logging.Level = "debug"
+	case logLevel != "":
+		cfg.Logging.Level = logLevel
+	}
+
 	// Configurar nível de log
 	if cfg.Logging.Level != "" {
 		level, err := logrus.ParseLevel(cfg.Logging.Level)
@@ -227,22 +1420,137 @@ func setupLogging(cfg *config.Config) {
 		}
 	}
 
+	if cfg.Logging.Format == config.LogFormatJSON {
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	}
+
+	// O token pode vir de token_command ou token_file e nunca deve aparecer nos logs
+	logrus.AddHook(newSecretRedactHook(cfg.TerraformCloud.Token))
+
 	// Configurar arquivo de log se especificado
 	if cfg.Logging.File != "" {
+		if err := os.MkdirAll(filepath.Dir(cfg.Logging.File), 0755); err != nil {
+			return fmt.Errorf("erro ao criar diretório do arquivo de log %s: %w", cfg.Logging.File, err)
+		}
+
 		file, err := os.OpenFile(cfg.Logging.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-		if err == nil {
-			logrus.SetOutput(file)
+		if err != nil {
+			return fmt.Errorf("erro ao abrir arquivo de log %s: %w", cfg.Logging.File, err)
 		}
+		logrus.SetOutput(file)
 	}
+
+	return nil
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
-		os.Exit(1)
+	err := rootCmd.Execute()
+	cancelTimeout()
+
+	if err != nil && timedOut() {
+		err = withExitCode(exitTimeout, err)
+	}
+
+	if err != nil {
+		var exitErr *exitError
+		if !errors.As(err, &exitErr) || exitErr.err != nil {
+			fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		}
+		os.Exit(exitCode(err))
 	}
 }
 
 func main() {
 	Execute()
 }
+
+func runInit(cmd *cobra.Command, args []string) error {
+	path := cfgFile
+	if path == "" {
+		path = "config.yaml"
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if !force {
+			return fmt.Errorf("arquivo %s já existe, use --force para sobrescrevê-lo", path)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("erro ao verificar arquivo %s: %w", path, err)
+	}
+
+	reader := bufio.NewReader(cmd.InOrStdin())
+	out := cmd.OutOrStdout()
+
+	var cfg config.Config
+	var err error
+
+	if cfg.TerraformCloud.Organization, err = prompt(reader, out, "Organização do Terraform Cloud", initOrganization, ""); err != nil {
+		return err
+	}
+
+	// O token só é perguntado se não foi informado e o usuário optar por gravá-lo no arquivo
+	cfg.TerraformCloud.Token = initToken
+	if cfg.TerraformCloud.Token == "" && !tokenFromEnv {
+		answer, err := prompt(reader, out, "Gravar o token do Terraform Cloud no arquivo? (s/n)", "", "n")
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(strings.ToLower(answer), "s") {
+			if cfg.TerraformCloud.Token, err = prompt(reader, out, "Token do Terraform Cloud", "", ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	if cfg.AWS.Bucket, err = prompt(reader, out, "Bucket S3", initBucket, ""); err != nil {
+		return err
+	}
+	if cfg.AWS.Region, err = prompt(reader, out, "Região AWS", initRegion, "us-east-1"); err != nil {
+		return err
+	}
+	if cfg.AWS.Prefix, err = prompt(reader, out, "Prefixo no bucket", initPrefix, "terraform-states/"); err != nil {
+		return err
+	}
+
+	if err := cfg.SaveConfig(path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nConfiguração gravada em %s\n", path)
+	if cfg.TerraformCloud.Token == "" {
+		fmt.Fprintln(out, "O token não foi gravado: defina a variável de ambiente TFC_TOKEN antes de executar o migrator.")
+	}
+
+	return nil
+}
+
+// prompt retorna o valor da flag, se informado, ou pergunta o valor ao usuário.
+// Uma resposta vazia usa o valor padrão; sem valor padrão, a resposta é obrigatória.
+func prompt(reader *bufio.Reader, out io.Writer, label, flagValue, defaultValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+
+	if defaultValue != "" {
+		fmt.Fprintf(out, "%s [%s]: ", label, defaultValue)
+	} else {
+		fmt.Fprintf(out, "%s: ", label)
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("erro ao ler resposta: %w", err)
+	}
+
+	value := strings.TrimSpace(line)
+	if value == "" {
+		value = defaultValue
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("valor obrigatório não informado: %s", label)
+	}
+
+	return value, nil
+}
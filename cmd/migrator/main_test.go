@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"terraform-cloud-s3-migrator/internal/config"
+)
+
+func TestReadProjectsFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "workspaces com comentários",
+			content: "# onda 1\nnetwork\n\n  billing  \n# fim\n",
+			want:    []string{"network", "billing"},
+		},
+		{
+			name:    "apenas comentários e linhas vazias",
+			content: "# onda 1\n\n   \n# nada aqui\n",
+			wantErr: true,
+		},
+		{
+			name:    "arquivo vazio",
+			content: "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "projects.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := readProjectsFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("erro = %v, esperado erro: %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("projetos = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShowProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		quiet    bool
+		output   string
+		format   string
+		file     string
+		terminal bool
+		want     bool
+	}{
+		{name: "logs em arquivo", output: outputText, format: config.LogFormatText, file: "migration.log", terminal: true, want: true},
+		{name: "quiet", quiet: true, output: outputText, format: config.LogFormatText, file: "migration.log", terminal: true},
+		{name: "saída json", output: outputJSON, format: config.LogFormatText, file: "migration.log", terminal: true},
+		{name: "logs em json", output: outputText, format: config.LogFormatJSON, file: "migration.log", terminal: true},
+		{name: "logs no stderr", output: outputText, format: config.LogFormatText, terminal: true},
+		{name: "stderr redirecionado", output: outputText, format: config.LogFormatText, file: "migration.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(o string, q bool) { output, quiet = o, q }(output, quiet)
+			output, quiet = tt.output, tt.quiet
+
+			cfg := &config.Config{Logging: config.LoggingConfig{Format: tt.format, File: tt.file}}
+			if got := showProgress(cfg, tt.terminal); got != tt.want {
+				t.Errorf("showProgress = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+
+	"terraform-cloud-s3-migrator/internal/redact"
+
+	"github.com/sirupsen/logrus"
+)
+
+// secretRedactHook remove segredos conhecidos (como o token do Terraform Cloud) das mensagens
+// e dos campos de cada entrada de log, antes da formatação
+type secretRedactHook struct {
+	secrets []string
+}
+
+// newSecretRedactHook cria o hook ignorando valores vazios
+func newSecretRedactHook(secrets ...string) *secretRedactHook {
+	hook := &secretRedactHook{}
+	for _, secret := range secrets {
+		if secret != "" {
+			hook.secrets = append(hook.secrets, secret)
+		}
+	}
+	return hook
+}
+
+func (h *secretRedactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *secretRedactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = h.redact(entry.Message)
+
+	// Os campos são copiados para não alterar o mapa compartilhado por entradas derivadas do mesmo logger
+	fields := make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			fields[key] = h.redact(v)
+		case error:
+			fields[key] = h.redact(v.Error())
+		default:
+			fields[key] = value
+		}
+	}
+	entry.Data = fields
+
+	return nil
+}
+
+// redact substitui cada segredo encontrado no texto, além de URLs pré-assinadas e tokens Bearer
+func (h *secretRedactHook) redact(text string) string {
+	for _, secret := range h.secrets {
+		text = strings.ReplaceAll(text, secret, redact.Placeholder)
+	}
+	return redact.String(text)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"terraform-cloud-s3-migrator/internal/migrator"
+)
+
+// printMigrationSummary imprime no stdout o resumo final da migração em uma tabela alinhada. Com
+// --quiet ou --output json nada é impresso; no formato JSON o relatório de --report cobre o resumo.
+func printMigrationSummary(stats *migrator.MigrationStats, dryRun bool) {
+	if quiet || output == outputJSON {
+		return
+	}
+
+	title := "Resumo da migração"
+	if dryRun {
+		title = "Resumo do dry run"
+	}
+
+	successRate := "-"
+	if processed := stats.Successful + stats.Failed; processed > 0 {
+		successRate = fmt.Sprintf("%.1f%%", float64(stats.Successful)/float64(processed)*100)
+	}
+
+	fmt.Printf("\n %s:\n\n", title)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "   Total\t%d\n", stats.Total)
+	fmt.Fprintf(w, "   Sucesso\t%d\n", stats.Successful)
+	fmt.Fprintf(w, "   Falhas\t%d\n", stats.Failed)
+	fmt.Fprintf(w, "   Pulados\t%d\n", stats.Skipped)
+	fmt.Fprintf(w, "   Duração\t%s\n", stats.Duration.Round(time.Millisecond))
+	fmt.Fprintf(w, "   Taxa de sucesso\t%s\n", successRate)
+	w.Flush()
+
+	if len(stats.FailedItems) == 0 {
+		return
+	}
+
+	fmt.Printf("\n Workspaces com falha:\n\n")
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "   WORKSPACE\tCATEGORIA\tERRO")
+	for _, failure := range stats.FailedItems {
+		fmt.Fprintf(w, "   %s\t%s\t%s\n", failure.WorkspaceName, failure.Category, failure.Error)
+	}
+	w.Flush()
+}
+
+// printStateDiff imprime as diferenças em nível de recurso entre o estado do Terraform Cloud e o do S3
+func printStateDiff(workspaceName string, diff *migrator.StateDiff) {
+	fmt.Printf("\n Diferenças em %s (Terraform Cloud → S3):\n", workspaceName)
+	fmt.Printf("   • Serial: %d → %d\n", diff.SourceSerial, diff.TargetSerial)
+	if diff.SourceLineage == diff.TargetLineage {
+		fmt.Printf("   • Lineage: %s (igual)\n", diff.SourceLineage)
+	} else {
+		fmt.Printf("   • Lineage: %s → %s\n", diff.SourceLineage, diff.TargetLineage)
+	}
+	fmt.Printf("   • Recursos: %d → %d\n", diff.SourceResources, diff.TargetResources)
+
+	for _, address := range diff.Added {
+		fmt.Printf("     + %s (apenas no Terraform Cloud)\n", address)
+	}
+	for _, address := range diff.Removed {
+		fmt.Printf("     - %s (apenas no S3)\n", address)
+	}
+}
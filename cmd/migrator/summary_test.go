@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"terraform-cloud-s3-migrator/internal/migrator"
+)
+
+// captureStdout retorna o que fn imprime no stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestPrintMigrationSummary(t *testing.T) {
+	stats := &migrator.MigrationStats{
+		Total:      2,
+		Successful: 1,
+		Failed:     1,
+		FailedItems: []migrator.FailedMigration{
+			{WorkspaceName: "app-dev", Category: "upload_failed", Error: "acesso negado"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		quiet  bool
+		want   []string
+	}{
+		{name: "texto", output: outputText, want: []string{"Resumo da migração", "50.0%", "app-dev"}},
+		{name: "json não imprime", output: outputJSON},
+		{name: "quiet não imprime", output: outputText, quiet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(o string, q bool) { output, quiet = o, q }(output, quiet)
+			output, quiet = tt.output, tt.quiet
+
+			got := captureStdout(t, func() { printMigrationSummary(stats, false) })
+
+			if len(tt.want) == 0 && got != "" {
+				t.Fatalf("stdout deveria estar vazio, obtido %q", got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("stdout sem %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
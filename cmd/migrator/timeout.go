@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// errTimeout é a causa do cancelamento do contexto do comando ao esgotar --timeout
+var errTimeout = errors.New("tempo limite definido em --timeout excedido")
+
+// commandCtx guarda o contexto com o prazo de --timeout, consultado por Execute para definir o
+// código de saída; cancelTimeout libera o temporizador ao fim do comando
+var (
+	commandCtx    context.Context
+	cancelTimeout context.CancelFunc = func() {}
+)
+
+// persistentPreRun valida as flags globais e aplica --timeout ao contexto do comando
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+	if err := checkDryRun(cmd, args); err != nil {
+		return err
+	}
+
+	return applyTimeout(cmd)
+}
+
+// applyTimeout substitui o contexto do comando por um com o prazo de --timeout.
+// O contexto é repassado a Migrate, ListWorkspaces e demais operações via cmd.Context().
+func applyTimeout(cmd *cobra.Command) error {
+	if timeout < 0 {
+		return withExitCode(exitConfigError, fmt.Errorf("valor inválido para --timeout: %s (use uma duração positiva como 30m)", timeout))
+	}
+	if timeout == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeoutCause(cmd.Context(), timeout, errTimeout)
+	cmd.SetContext(ctx)
+	commandCtx = ctx
+	cancelTimeout = cancel
+
+	return nil
+}
+
+// timedOut indica se o comando foi cancelado por esgotar o prazo de --timeout
+func timedOut() bool {
+	return commandCtx != nil && errors.Is(context.Cause(commandCtx), errTimeout)
+}
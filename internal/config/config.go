@@ -1,11 +1,58 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
 )
 
+// accountIDPattern valida IDs de conta AWS (12 dígitos)
+var accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+
+// reservedMetadataKeys são as chaves de metadados gravadas pelo migrator, que não podem ser
+// sobrescritas por migration.extra_metadata
+var reservedMetadataKeys = []string{"workspace", "organization", "file-type", "source"}
+
+// Limites de tags do S3: no máximo 10 por objeto, com chaves de até 128 e valores de até 256
+// caracteres
+const (
+	maxObjectTags        = 10
+	maxObjectTagKeyLen   = 128
+	maxObjectTagValueLen = 256
+)
+
+// automaticObjectTags são as tags adicionadas pelo migrator a todos os objetos, que contam no
+// limite do S3
+var automaticObjectTags = []string{"migrated-by", "organization"}
+
+// Formatos aceitos em logging.format
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Regras aceitas em migration.name_rules
+const (
+	NameRuleLowercase = "lowercase" // Converte o nome para minúsculas
+	NameRuleSanitize  = "sanitize"  // Substitui caracteres fora de [A-Za-z0-9._-] por "-"
+	NameRuleCollapse  = "collapse"  // Reduz hífens repetidos a um e remove hífens nas pontas
+)
+
+// regionPattern valida identificadores de região AWS (ex: us-east-1, ap-southeast-2, us-gov-west-1)
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
 type Config struct {
 	TerraformCloud TerraformCloudConfig `mapstructure:"terraform_cloud"`
 	AWS            AWSConfig            `mapstructure:"aws"`
@@ -16,6 +63,12 @@ type Config struct {
 type TerraformCloudConfig struct {
 	Token        string `mapstructure:"token"`
 	Organization string `mapstructure:"organization"`
+	Address      string `mapstructure:"address"`
+
+	// Fontes alternativas do token, usadas quando token não está definido no arquivo.
+	// Precedência: token > token_command > token_file > TFC_TOKEN
+	TokenCommand string `mapstructure:"token_command"`
+	TokenFile    string `mapstructure:"token_file"`
 }
 
 type AWSConfig struct {
@@ -24,47 +77,192 @@ type AWSConfig struct {
 	Prefix    string `mapstructure:"prefix"`
 	Profile   string `mapstructure:"profile"`
 	AccountID string `mapstructure:"accountid"`
+	KMSKeyID  string `mapstructure:"kms_key_id"`
+
+	// Tabela DynamoDB de lock usada pelo backend S3 do Terraform
+	DynamoDBTable string `mapstructure:"dynamodb_table"`
+
+	// Layout das chaves no S3, com os placeholders {prefix}, {organization},
+	// {account_id}, {workspace} e {filename}
+	KeyTemplate string `mapstructure:"key_template"`
+
+	// Nomes dos arquivos de estado e de metadados usados em {filename}, com o placeholder {workspace} opcional
+	StateFilename    string `mapstructure:"state_filename"`
+	MetadataFilename string `mapstructure:"metadata_filename"`
+
+	// Tamanho das partes (em MB) e número de partes enviadas em paralelo no multipart upload
+	UploadPartSizeMB  int64 `mapstructure:"upload_part_size_mb"`
+	UploadConcurrency int   `mapstructure:"upload_concurrency"`
+
+	// Tags aplicadas aos objetos enviados (estado e metadados)
+	ObjectTags map[string]string `mapstructure:"object_tags"`
+
+	// Classe de armazenamento dos objetos enviados (ex: STANDARD, STANDARD_IA, INTELLIGENT_TIERING)
+	StorageClass string `mapstructure:"storage_class"`
+
+	// Endpoint S3 compatível (ex: MinIO ou LocalStack); usa path-style quando definido
+	EndpointURL string `mapstructure:"endpoint_url"`
+
+	// Role assumida para acessar o bucket em outra conta (opcional)
+	RoleARN    string `mapstructure:"role_arn"`
+	ExternalID string `mapstructure:"external_id"`
+
+	// Destino por sufixo de ambiente do workspace (ex: "-prd"); workspaces sem sufixo mapeado
+	// usam bucket e prefix
+	EnvironmentBuckets map[string]EnvironmentBucket `mapstructure:"environment_buckets"`
+}
+
+// EnvironmentBucket é o destino no S3 dos workspaces de um sufixo de ambiente.
+// Sem prefix, é usado aws.prefix.
+type EnvironmentBucket struct {
+	Bucket string `mapstructure:"bucket"`
+	Prefix string `mapstructure:"prefix"`
 }
 
 type MigrationConfig struct {
 	BatchSize         int `mapstructure:"batch_size"`
 	ConcurrentUploads int `mapstructure:"concurrent_uploads"`
 	RetryAttempts     int `mapstructure:"retry_attempts"`
+
+	// Verificações simultâneas de existência no S3 antes da migração
+	ScanConcurrency int `mapstructure:"scan_concurrency"`
+
+	// Limite de banda, em megabits por segundo, somando todos os uploads simultâneos (zero desativa)
+	MaxBandwidthMbps float64 `mapstructure:"max_bandwidth_mbps"`
+
+	// Atraso inicial e máximo do backoff exponencial entre tentativas
+	RetryBaseDelay time.Duration `mapstructure:"retry_base_delay"`
+	RetryMaxDelay  time.Duration `mapstructure:"retry_max_delay"`
+
+	// Número de falhas tolerado antes de abortar a execução (zero desativa)
+	MaxFailures int `mapstructure:"max_failures"`
+
+	// Tempo máximo de cada tentativa de transferência de um workspace (zero desativa)
+	OperationTimeout time.Duration `mapstructure:"operation_timeout"`
+
+	// Tempo máximo de cada download de estado do Terraform Cloud, incluindo a leitura do conteúdo (zero desativa)
+	DownloadTimeout time.Duration `mapstructure:"download_timeout"`
+
+	// Tamanho máximo de um estado baixado, em MB (zero desativa)
+	MaxStateSizeMB int64 `mapstructure:"max_state_size_mb"`
+
+	// Intervalo entre os logs periódicos de andamento da migração (zero desativa)
+	ProgressInterval time.Duration `mapstructure:"progress_interval"`
+
+	// Intervalo entre as gravações do arquivo de progresso (--progress-file)
+	ProgressFileInterval time.Duration `mapstructure:"progress_file_interval"`
+
+	// Limite global de requisições por segundo ao Terraform Cloud (zero desativa)
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+
+	// Sufixos de ambiente removidos do nome do workspace para formar a chave no S3
+	StripSuffixes       bool     `mapstructure:"strip_suffixes"`
+	EnvironmentSuffixes []string `mapstructure:"environment_suffixes"`
+
+	// Regras de normalização aplicadas ao nome após a remoção do sufixo (lowercase, sanitize, collapse)
+	NameRules []string `mapstructure:"name_rules"`
+
+	// Arquivo de checkpoint usado para retomar migrações interrompidas (vazio desativa)
+	CheckpointFile string `mapstructure:"checkpoint_file"`
+
+	// Manifesto que mapeia cada workspace para a chave no S3 (vazio desativa o arquivo local)
+	// e se ele também é gravado no bucket como _manifest.json
+	ManifestFile   string `mapstructure:"manifest_file"`
+	UploadManifest bool   `mapstructure:"upload_manifest"`
+
+	// Arquivo JSONL, apenas com acréscimos, com uma linha por workspace processado (vazio desativa)
+	AuditLog string `mapstructure:"audit_log"`
+
+	// Comprime o estado com gzip antes do upload (chave terraform.tfstate.gz)
+	Compress bool `mapstructure:"compress"`
+
+	// Migra também todas as versões anteriores do estado para <workspace>/history/
+	IncludeHistory bool `mapstructure:"include_history"`
+
+	// Grava as variáveis do workspace em variables.json (valores sensíveis são omitidos)
+	IncludeVariables bool `mapstructure:"include_variables"`
+
+	// Reenvia estados que já existem no S3 quando o serial no Terraform Cloud for diferente
+	Overwrite bool `mapstructure:"overwrite"`
+
+	// Metadados adicionais (ex: ticket e solicitante) gravados nos objetos do S3 e no metadata.json
+	ExtraMetadata map[string]string `mapstructure:"extra_metadata"`
 }
 
 type LoggingConfig struct {
 	Level string `mapstructure:"level"`
 	File  string `mapstructure:"file"`
+
+	// Formato dos logs: text (padrão, para uso interativo) ou json (para agregadores como o CloudWatch)
+	Format string `mapstructure:"format"`
 }
 
-// LoadConfig carrega a configuração do arquivo config.yaml ou variáveis de ambiente
-func LoadConfig() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("./config")
-	viper.AddConfigPath("$HOME/.terraform-migrator")
+// LoadConfig carrega a configuração do arquivo config.yaml ou variáveis de ambiente.
+// Se path for informado, apenas esse arquivo é lido e ele precisa existir.
+func LoadConfig(path string) (*Config, error) {
+	return loadConfig(path, true)
+}
+
+// LoadLocalConfig carrega a configuração para comandos que não acessam o S3,
+// dispensando o bucket de destino.
+func LoadLocalConfig(path string) (*Config, error) {
+	return loadConfig(path, false)
+}
+
+func loadConfig(path string, requireBucket bool) (*Config, error) {
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("arquivo de configuração %s não encontrado: %w", path, err)
+		}
+		viper.SetConfigFile(path)
+	} else {
+		viper.SetConfigName("config")
+		viper.SetConfigType("yaml")
+		viper.AddConfigPath(".")
+		viper.AddConfigPath("./config")
+		viper.AddConfigPath("$HOME/.terraform-migrator")
+	}
 
 	// Configurar variáveis de ambiente
 	viper.SetEnvPrefix("TFC")
-	viper.BindEnv("terraform_cloud.token", "TFC_TOKEN")
 	viper.BindEnv("terraform_cloud.organization", "TFC_ORGANIZATION")
+	viper.BindEnv("terraform_cloud.address", "TFC_ADDRESS")
 	viper.BindEnv("aws.region", "AWS_REGION")
 	viper.BindEnv("aws.bucket", "S3_BUCKET")
 	viper.BindEnv("aws.prefix", "S3_PREFIX")
 	viper.BindEnv("aws.accountid", "AWS_ACCOUNTID")
+	viper.BindEnv("aws.kms_key_id", "AWS_KMS_KEY_ID")
+	viper.BindEnv("aws.endpoint_url", "AWS_ENDPOINT_URL")
 
 	viper.AutomaticEnv()
 
 	// Definir valores padrão
 	viper.SetDefault("aws.region", "us-east-1")
 	viper.SetDefault("aws.prefix", "terraform-states/")
+	viper.SetDefault("aws.key_template", "{prefix}{account_id}/{workspace}/{filename}")
+	viper.SetDefault("aws.state_filename", "terraform.tfstate")
+	viper.SetDefault("aws.metadata_filename", "metadata.json")
+	viper.SetDefault("aws.upload_part_size_mb", 5)
+	viper.SetDefault("aws.upload_concurrency", 5)
+	viper.SetDefault("aws.storage_class", string(types.StorageClassStandard))
 	viper.SetDefault("migration.batch_size", 5)
 	viper.SetDefault("migration.concurrent_uploads", 3)
 	viper.SetDefault("migration.retry_attempts", 3)
+	viper.SetDefault("migration.scan_concurrency", 10)
+	viper.SetDefault("migration.retry_base_delay", "1s")
+	viper.SetDefault("migration.retry_max_delay", "30s")
+	viper.SetDefault("migration.operation_timeout", "10m")
+	viper.SetDefault("migration.download_timeout", "5m")
+	viper.SetDefault("migration.max_state_size_mb", 1024)
+	viper.SetDefault("migration.progress_interval", "10s")
+	viper.SetDefault("migration.progress_file_interval", "10s")
+	viper.SetDefault("migration.requests_per_second", 10)
+	viper.SetDefault("migration.strip_suffixes", true)
+	viper.SetDefault("migration.checkpoint_file", "migration-checkpoint.json")
+	viper.SetDefault("migration.manifest_file", "manifest.json")
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.file", "migration.log")
-	viper.SetDefault("aws.accountid", "339712781224")
+	viper.SetDefault("logging.format", LogFormatText)
 
 	// Tentar ler o arquivo de configuração
 	if err := viper.ReadInConfig(); err != nil {
@@ -78,28 +276,206 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("erro ao deserializar configuração: %w", err)
 	}
 
+	if tags, ok := rawObjectTags(viper.ConfigFileUsed()); ok {
+		config.AWS.ObjectTags = tags
+	}
+
+	if err := config.TerraformCloud.resolveToken(); err != nil {
+		return nil, err
+	}
+
+	if err := config.expandPaths(); err != nil {
+		return nil, err
+	}
+
 	// Validar configurações obrigatórias
-	if err := config.Validate(); err != nil {
+	if err := config.validate(requireBucket); err != nil {
 		return nil, err
 	}
 
 	return &config, nil
 }
 
+// rawObjectTags relê aws.object_tags do arquivo de configuração preservando a caixa das chaves,
+// que o viper converte para minúsculas. Apenas tags definidas no arquivo mantêm a caixa. Retorna
+// false se o arquivo não puder ser lido como YAML ou não definir aws.object_tags, mantendo o valor
+// resolvido pelo viper.
+func rawObjectTags(path string) (map[string]string, bool) {
+	if path == "" {
+		return nil, false
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var raw struct {
+		AWS struct {
+			ObjectTags map[string]string `yaml:"object_tags"`
+		} `yaml:"aws"`
+	}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, false
+	}
+
+	return raw.AWS.ObjectTags, raw.AWS.ObjectTags != nil
+}
+
+// expandPaths expande ~ e variáveis de ambiente nos campos de caminho da configuração
+func (c *Config) expandPaths() error {
+	fields := map[string]*string{
+		"logging.file":              &c.Logging.File,
+		"migration.checkpoint_file": &c.Migration.CheckpointFile,
+		"migration.manifest_file":   &c.Migration.ManifestFile,
+		"migration.audit_log":       &c.Migration.AuditLog,
+	}
+
+	for name, field := range fields {
+		path, err := ExpandPath(*field)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		*field = path
+	}
+
+	return nil
+}
+
+// ExpandPath substitui ~ pelo diretório home do usuário e expande $VAR e ${VAR} no caminho
+func ExpandPath(path string) (string, error) {
+	path = os.ExpandEnv(path)
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("erro ao obter diretório home para expandir %s: %w", path, err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+
+	return path, nil
+}
+
+// resolveToken obtém o token das fontes alternativas, na ordem de precedência, quando ele não está no arquivo.
+// As mensagens de erro nunca incluem a saída do comando ou o conteúdo do arquivo.
+func (c *TerraformCloudConfig) resolveToken() error {
+	switch {
+	case c.Token != "":
+		return nil
+	case c.TokenCommand != "":
+		var stderr bytes.Buffer
+		cmd := exec.Command("sh", "-c", c.TokenCommand)
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("erro ao executar token_command: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		c.Token = strings.TrimSpace(string(output))
+		if c.Token == "" {
+			return fmt.Errorf("token_command não retornou um token")
+		}
+	case c.TokenFile != "":
+		content, err := os.ReadFile(c.TokenFile)
+		if err != nil {
+			return fmt.Errorf("erro ao ler token_file: %w", err)
+		}
+		c.Token = strings.TrimSpace(string(content))
+		if c.Token == "" {
+			return fmt.Errorf("token_file %s está vazio", c.TokenFile)
+		}
+	default:
+		c.Token = os.Getenv("TFC_TOKEN")
+	}
+
+	return nil
+}
+
 // Validate valida se todas as configurações obrigatórias estão presentes
 func (c *Config) Validate() error {
+	return c.validate(true)
+}
+
+func (c *Config) validate(requireBucket bool) error {
 	if c.TerraformCloud.Token == "" {
-		return fmt.Errorf("token do Terraform Cloud é obrigatório")
+		return fmt.Errorf("token do Terraform Cloud é obrigatório (token, token_command, token_file ou TFC_TOKEN)")
 	}
 
 	if c.TerraformCloud.Organization == "" {
 		return fmt.Errorf("organização do Terraform Cloud é obrigatória")
 	}
 
-	if c.AWS.Bucket == "" {
+	if c.TerraformCloud.Address != "" {
+		address, err := url.Parse(c.TerraformCloud.Address)
+		if err != nil || address.Scheme != "https" || address.Host == "" {
+			return fmt.Errorf("endereço do Terraform Cloud/Enterprise deve ser uma URL https válida: %s", c.TerraformCloud.Address)
+		}
+	}
+
+	if requireBucket && c.AWS.Bucket == "" {
 		return fmt.Errorf("bucket S3 é obrigatório")
 	}
 
+	if c.AWS.EndpointURL != "" {
+		endpoint, err := url.Parse(c.AWS.EndpointURL)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return fmt.Errorf("endpoint_url deve ser uma URL http(s) válida: %s", c.AWS.EndpointURL)
+		}
+	} else if !regionPattern.MatchString(c.AWS.Region) {
+		// Endpoints compatíveis com S3 (ex: MinIO) aceitam regiões fora do padrão da AWS
+		return fmt.Errorf("região AWS inválida: %q (esperado um identificador como us-east-1; para regiões fora do padrão configure aws.endpoint_url)", c.AWS.Region)
+	}
+
+	for suffix, destination := range c.AWS.EnvironmentBuckets {
+		if strings.TrimSpace(suffix) == "" {
+			return fmt.Errorf("environment_buckets não aceita sufixo vazio")
+		}
+		if destination.Bucket == "" {
+			return fmt.Errorf("environment_buckets.%s: bucket é obrigatório", suffix)
+		}
+	}
+
+	if err := validateObjectTags(c.AWS.ObjectTags); err != nil {
+		return err
+	}
+
+	if c.AWS.ExternalID != "" && c.AWS.RoleARN == "" {
+		return fmt.Errorf("external_id requer role_arn configurado")
+	}
+
+	if c.AWS.AccountID != "" && !accountIDPattern.MatchString(c.AWS.AccountID) {
+		return fmt.Errorf("accountid deve conter exatamente 12 dígitos numéricos: %s", c.AWS.AccountID)
+	}
+
+	if c.AWS.KeyTemplate != "" && (!strings.Contains(c.AWS.KeyTemplate, "{workspace}") || !strings.Contains(c.AWS.KeyTemplate, "{filename}")) {
+		return fmt.Errorf("key_template deve conter os placeholders {workspace} e {filename}: %s", c.AWS.KeyTemplate)
+	}
+
+	if c.AWS.StateFilename == "" || c.AWS.MetadataFilename == "" {
+		return fmt.Errorf("state_filename e metadata_filename não podem ser vazios")
+	}
+
+	if c.AWS.StateFilename == c.AWS.MetadataFilename {
+		return fmt.Errorf("state_filename e metadata_filename devem ser diferentes: %s", c.AWS.StateFilename)
+	}
+
+	// O S3 exige partes de pelo menos 5 MB no multipart upload
+	if c.AWS.UploadPartSizeMB < 5 {
+		return fmt.Errorf("upload_part_size_mb deve ser maior ou igual a 5")
+	}
+
+	if c.AWS.UploadConcurrency <= 0 {
+		return fmt.Errorf("upload_concurrency deve ser maior que 0")
+	}
+
+	if !validStorageClass(c.AWS.StorageClass) {
+		return fmt.Errorf("storage_class inválida: %s (valores aceitos: %v)", c.AWS.StorageClass, types.StorageClass("").Values())
+	}
+
+	if c.Logging.Format != LogFormatText && c.Logging.Format != LogFormatJSON {
+		return fmt.Errorf("logging.format inválido: %s (valores aceitos: %s, %s)", c.Logging.Format, LogFormatText, LogFormatJSON)
+	}
+
 	if c.Migration.BatchSize <= 0 {
 		return fmt.Errorf("batch_size deve ser maior que 0")
 	}
@@ -108,20 +484,145 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("concurrent_uploads deve ser maior que 0")
 	}
 
+	for key := range c.Migration.ExtraMetadata {
+		for _, reserved := range reservedMetadataKeys {
+			if strings.EqualFold(key, reserved) {
+				return fmt.Errorf("chave reservada em extra_metadata: %s (reservadas: %s)", key, strings.Join(reservedMetadataKeys, ", "))
+			}
+		}
+	}
+
+	for _, rule := range c.Migration.NameRules {
+		if rule != NameRuleLowercase && rule != NameRuleSanitize && rule != NameRuleCollapse {
+			return fmt.Errorf("regra de normalização inválida em name_rules: %s (valores aceitos: %s, %s, %s)", rule, NameRuleLowercase, NameRuleSanitize, NameRuleCollapse)
+		}
+	}
+
+	if c.Migration.MaxFailures < 0 {
+		return fmt.Errorf("max_failures não pode ser negativo")
+	}
+
+	if c.Migration.ScanConcurrency <= 0 {
+		return fmt.Errorf("scan_concurrency deve ser maior que 0")
+	}
+
+	if c.Migration.MaxBandwidthMbps < 0 {
+		return fmt.Errorf("max_bandwidth_mbps não pode ser negativo")
+	}
+
+	if c.Migration.OperationTimeout < 0 {
+		return fmt.Errorf("operation_timeout não pode ser negativo")
+	}
+
+	if c.Migration.DownloadTimeout < 0 {
+		return fmt.Errorf("download_timeout não pode ser negativo")
+	}
+
+	if c.Migration.MaxStateSizeMB < 0 {
+		return fmt.Errorf("max_state_size_mb não pode ser negativo")
+	}
+
+	if c.Migration.ProgressInterval < 0 {
+		return fmt.Errorf("progress_interval não pode ser negativo")
+	}
+
+	if c.Migration.ProgressFileInterval < 0 {
+		return fmt.Errorf("progress_file_interval não pode ser negativo")
+	}
+
+	if c.Migration.RequestsPerSecond < 0 {
+		return fmt.Errorf("requests_per_second não pode ser negativo")
+	}
+
+	if c.Migration.RetryBaseDelay < 0 || c.Migration.RetryMaxDelay < c.Migration.RetryBaseDelay {
+		return fmt.Errorf("retry_max_delay deve ser maior ou igual a retry_base_delay")
+	}
+
 	return nil
 }
 
+// validStorageClass verifica se a classe de armazenamento é suportada pelo S3
+func validStorageClass(storageClass string) bool {
+	for _, value := range types.StorageClass("").Values() {
+		if string(value) == storageClass {
+			return true
+		}
+	}
+	return false
+}
+
 // GetConfigPath retorna o caminho do arquivo de configuração sendo usado
 func GetConfigPath() string {
 	return viper.ConfigFileUsed()
 }
 
-// SaveConfig salva a configuração atual no arquivo
-func (c *Config) SaveConfig() error {
-	viper.Set("terraform_cloud", c.TerraformCloud)
-	viper.Set("aws", c.AWS)
-	viper.Set("migration", c.Migration)
-	viper.Set("logging", c.Logging)
+// SaveConfig salva as configurações principais no arquivo informado.
+// Campos vazios não são gravados, de modo que os valores padrão e as variáveis de ambiente se aplicam ao carregar.
+func (c *Config) SaveConfig(path string) error {
+	v := viper.New()
+
+	if c.TerraformCloud.Token != "" {
+		v.Set("terraform_cloud.token", c.TerraformCloud.Token)
+	}
+	v.Set("terraform_cloud.organization", c.TerraformCloud.Organization)
+	if c.TerraformCloud.Address != "" {
+		v.Set("terraform_cloud.address", c.TerraformCloud.Address)
+	}
+
+	v.Set("aws.region", c.AWS.Region)
+	v.Set("aws.bucket", c.AWS.Bucket)
+	v.Set("aws.prefix", c.AWS.Prefix)
+	if c.AWS.Profile != "" {
+		v.Set("aws.profile", c.AWS.Profile)
+	}
+
+	if c.Migration.BatchSize > 0 {
+		v.Set("migration.batch_size", c.Migration.BatchSize)
+	}
+	if c.Migration.ConcurrentUploads > 0 {
+		v.Set("migration.concurrent_uploads", c.Migration.ConcurrentUploads)
+	}
+	if c.Migration.RetryAttempts > 0 {
+		v.Set("migration.retry_attempts", c.Migration.RetryAttempts)
+	}
+
+	if c.Logging.Level != "" {
+		v.Set("logging.level", c.Logging.Level)
+	}
+
+	// O arquivo pode conter o token do Terraform Cloud
+	v.SetConfigType("yaml")
+	v.SetConfigPermissions(0600)
+
+	if err := v.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("erro ao gravar configuração %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// validateObjectTags verifica os limites do S3 para object_tags, considerando as tags automáticas,
+// para que o upload não falhe apenas durante a migração
+func validateObjectTags(tags map[string]string) error {
+	count := len(automaticObjectTags)
+	for key, value := range tags {
+		if key == "" {
+			return fmt.Errorf("object_tags não aceita chave vazia")
+		}
+		if utf8.RuneCountInString(key) > maxObjectTagKeyLen {
+			return fmt.Errorf("object_tags: chave %q excede %d caracteres", key, maxObjectTagKeyLen)
+		}
+		if utf8.RuneCountInString(value) > maxObjectTagValueLen {
+			return fmt.Errorf("object_tags.%s: valor excede %d caracteres", key, maxObjectTagValueLen)
+		}
+		if !slices.Contains(automaticObjectTags, key) {
+			count++
+		}
+	}
 
-	return viper.WriteConfig()
+	if count > maxObjectTags {
+		return fmt.Errorf("object_tags: %d tags excedem o limite de %d por objeto do S3 (incluindo %s, adicionadas automaticamente)", count, maxObjectTags, strings.Join(automaticObjectTags, " e "))
+	}
+
+	return nil
 }
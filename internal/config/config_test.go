@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// loadTestConfig grava content em um config.yaml temporário, acrescido das chaves obrigatórias,
+// e o carrega com uma instância limpa do viper
+func loadTestConfig(t *testing.T, content string) (*Config, error) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	base := "terraform_cloud:\n  token: test-token\n  organization: acme\n"
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(base+content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return LoadLocalConfig(path)
+}
+
+func TestProgressFileInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "padrão", content: ""},
+		{name: "zero desativa a gravação periódica", content: "migration:\n  progress_file_interval: 0s\n"},
+		{name: "negativo", content: "migration:\n  progress_file_interval: -1s\n", wantErr: "progress_file_interval"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTestConfig(t, tt.content)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("erro inesperado: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("erro = %v, esperado contendo %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestObjectTags(t *testing.T) {
+	manyTags := "aws:\n  object_tags:\n"
+	for i := 0; i < 9; i++ {
+		manyTags += fmt.Sprintf("    tag-%d: valor\n", i)
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		wantTags map[string]string
+		wantErr  string
+	}{
+		{
+			name:     "preserva a caixa das chaves",
+			content:  "aws:\n  object_tags:\n    CostCenter: Platform\n    team: infra\n",
+			wantTags: map[string]string{"CostCenter": "Platform", "team": "infra"},
+		},
+		{
+			name:    "excede o limite com as tags automáticas",
+			content: manyTags,
+			wantErr: "limite de 10",
+		},
+		{
+			name:    "chave longa",
+			content: "aws:\n  object_tags:\n    " + strings.Repeat("k", 129) + ": valor\n",
+			wantErr: "excede 128",
+		},
+		{
+			name:    "valor longo",
+			content: "aws:\n  object_tags:\n    team: " + strings.Repeat("v", 257) + "\n",
+			wantErr: "excede 256",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadTestConfig(t, tt.content)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("erro = %v, esperado contendo %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if !maps.Equal(cfg.AWS.ObjectTags, tt.wantTags) {
+				t.Fatalf("object_tags = %v, esperado %v", cfg.AWS.ObjectTags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestRawObjectTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantOK  bool
+	}{
+		{name: "com object_tags", content: "aws:\n  object_tags:\n    CostCenter: Platform\n", want: map[string]string{"CostCenter": "Platform"}, wantOK: true},
+		{name: "sem object_tags", content: "aws:\n  bucket: states\n"},
+		{name: "não é YAML", content: "aws = [\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, ok := rawObjectTags(path)
+			if ok != tt.wantOK || !maps.Equal(got, tt.want) {
+				t.Fatalf("rawObjectTags = %v, %v; esperado %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// jobName identifica as métricas do migrator no Pushgateway
+const jobName = "terraform-cloud-s3-migrator"
+
+// durationBuckets são os limites (em segundos) do histograma de duração por workspace
+var durationBuckets = []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// Run resume uma execução da migração para envio ao Pushgateway
+type Run struct {
+	Organization string
+	Successful   int
+	Failed       int
+	Durations    []time.Duration
+	Bytes        int64
+}
+
+// Push envia as métricas da execução ao Prometheus Pushgateway no formato de texto do Prometheus,
+// substituindo as métricas anteriores do mesmo job e organização
+func Push(ctx context.Context, gatewayURL string, run Run) error {
+	endpoint := fmt.Sprintf("%s/metrics/job/%s/organization/%s",
+		strings.TrimSuffix(gatewayURL, "/"), jobName, url.PathEscape(run.Organization))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(run.encode()))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição para o Pushgateway: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao enviar métricas ao Pushgateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Pushgateway respondeu com status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// encode serializa as métricas no formato de exposição em texto do Prometheus
+func (r Run) encode() []byte {
+	var buf bytes.Buffer
+
+	writeMetric(&buf, "migrations_total", "counter", "Workspaces processados na migração.", float64(r.Successful+r.Failed))
+	writeMetric(&buf, "migrations_failed_total", "counter", "Workspaces cuja migração falhou.", float64(r.Failed))
+	writeMetric(&buf, "migration_bytes_total", "counter", "Bytes de estado transferidos ao S3.", float64(r.Bytes))
+
+	name := "migration_workspace_duration_seconds"
+	fmt.Fprintf(&buf, "# HELP %s Duração da migração de cada workspace.\n", name)
+	fmt.Fprintf(&buf, "# TYPE %s histogram\n", name)
+
+	var sum float64
+	counts := make([]int, len(durationBuckets))
+	for _, duration := range r.Durations {
+		seconds := duration.Seconds()
+		sum += seconds
+		for i, bound := range durationBuckets {
+			if seconds <= bound {
+				counts[i]++
+			}
+		}
+	}
+	for i, bound := range durationBuckets {
+		fmt.Fprintf(&buf, "%s_bucket{le=\"%g\"} %d\n", name, bound, counts[i])
+	}
+	fmt.Fprintf(&buf, "%s_bucket{le=\"+Inf\"} %d\n", name, len(r.Durations))
+	fmt.Fprintf(&buf, "%s_sum %g\n", name, sum)
+	fmt.Fprintf(&buf, "%s_count %d\n", name, len(r.Durations))
+
+	return buf.Bytes()
+}
+
+// writeMetric escreve uma métrica simples, sem labels
+func writeMetric(buf *bytes.Buffer, name, kind, help string, value float64) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, kind)
+	fmt.Fprintf(buf, "%s %g\n", name, value)
+}
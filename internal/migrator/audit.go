@@ -0,0 +1,77 @@
+package migrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Resultados registrados no audit log
+const (
+	auditOutcomeSuccess = "success"
+	auditOutcomeFailed  = "failed"
+)
+
+// auditEntry é uma linha do audit log (migration.audit_log)
+type auditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Workspace string    `json:"workspace"`
+	S3Name    string    `json:"clean_name"`
+	Key       string    `json:"key,omitempty"`
+	Serial    int       `json:"serial"`
+	Bytes     int64     `json:"bytes"`
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// auditLog acrescenta um objeto JSON por linha ao arquivo, nunca reescrevendo o conteúdo anterior.
+// Cada linha é sincronizada no disco para que uma interrupção não perca os registros recentes.
+type auditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// openAuditLog abre o arquivo em modo append, criando-o e aos diretórios se necessário
+func openAuditLog(path string) (*auditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório do audit log %s: %w", path, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir audit log %s: %w", path, err)
+	}
+
+	return &auditLog{file: file}, nil
+}
+
+// write grava a entrada como uma linha terminada em \n
+func (a *auditLog) write(entry auditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar entrada do audit log: %w", err)
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, err := a.file.Write(line); err != nil {
+		return fmt.Errorf("erro ao gravar audit log: %w", err)
+	}
+	if err := a.file.Sync(); err != nil {
+		return fmt.Errorf("erro ao sincronizar audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (a *auditLog) close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.file.Close()
+}
@@ -0,0 +1,139 @@
+package migrator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// BackendConfig contém os dados do bloco backend "s3" de um workspace migrado
+type BackendConfig struct {
+	WorkspaceName string
+	Bucket        string
+	Key           string
+	Region        string
+	DynamoDBTable string
+	KMSKeyID      string
+}
+
+var backendTemplate = template.Must(template.New("backend").Parse(`# Workspace do Terraform Cloud: {{ .WorkspaceName }}
+terraform {
+  backend "s3" {
+    bucket  = "{{ .Bucket }}"
+    key     = "{{ .Key }}"
+    region  = "{{ .Region }}"
+    encrypt = true
+{{- if .DynamoDBTable }}
+    dynamodb_table = "{{ .DynamoDBTable }}"
+{{- end }}
+{{- if .KMSKeyID }}
+    kms_key_id     = "{{ .KMSKeyID }}"
+{{- end }}
+  }
+}
+`))
+
+// Render gera o conteúdo do arquivo backend.tf
+func (b BackendConfig) Render() (string, error) {
+	var buf bytes.Buffer
+	if err := backendTemplate.Execute(&buf, b); err != nil {
+		return "", fmt.Errorf("erro ao gerar backend do workspace %s: %w", b.WorkspaceName, err)
+	}
+	return buf.String(), nil
+}
+
+// GenerateBackends monta a configuração de backend S3 para cada workspace já migrado
+func (m *Migrator) GenerateBackends(ctx context.Context, options MigrationOptions) ([]BackendConfig, error) {
+	if err := m.validateDestinations(ctx); err != nil {
+		return nil, err
+	}
+
+	projectSet := make(map[string]bool, len(options.Projects))
+	for _, projectName := range options.Projects {
+		projectSet[projectName] = true
+	}
+
+	mf, err := m.loadManifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// O manifesto registra a chave exata de cada workspace; sem ele, os estados são listados no S3
+	if len(mf.Workspaces) > 0 {
+		return m.manifestBackends(mf, projectSet), nil
+	}
+
+	states, err := m.listStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var backends []BackendConfig
+	for _, st := range states {
+		workspaceName, _ := st.Metadata["workspace_name"].(string)
+		if workspaceName == "" {
+			workspaceName = st.WorkspaceName
+		}
+
+		if len(projectSet) > 0 && !projectSet[workspaceName] && !projectSet[st.WorkspaceName] {
+			continue
+		}
+
+		// O backend S3 do Terraform não lê estados comprimidos
+		if strings.HasSuffix(st.StateKey, ".gz") {
+			m.logger.WithField("workspace", workspaceName).Warn("Estado comprimido no S3 não é compatível com o backend S3, pulando")
+			continue
+		}
+
+		backends = append(backends, m.backendConfig(workspaceName, sinkName(st.destination), st.StateKey))
+	}
+
+	return backends, nil
+}
+
+// manifestBackends monta a configuração de backend a partir das entradas do manifesto, em ordem alfabética
+func (m *Migrator) manifestBackends(mf *manifest, projectSet map[string]bool) []BackendConfig {
+	names := make([]string, 0, len(mf.Workspaces))
+	for name := range mf.Workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var backends []BackendConfig
+	for _, name := range names {
+		entry := mf.Workspaces[name]
+		if len(projectSet) > 0 && !projectSet[entry.Workspace] && !projectSet[entry.S3Name] {
+			continue
+		}
+
+		// O backend S3 do Terraform não lê estados comprimidos
+		if strings.HasSuffix(entry.StateKey, ".gz") {
+			m.logger.WithField("workspace", entry.Workspace).Warn("Estado comprimido no S3 não é compatível com o backend S3, pulando")
+			continue
+		}
+
+		bucket := entry.Bucket
+		if bucket == "" {
+			bucket = m.config.AWS.Bucket
+		}
+
+		backends = append(backends, m.backendConfig(entry.Workspace, bucket, entry.StateKey))
+	}
+
+	return backends
+}
+
+// backendConfig monta o bloco backend de um workspace a partir do bucket e da chave do estado
+func (m *Migrator) backendConfig(workspaceName, bucket, stateKey string) BackendConfig {
+	return BackendConfig{
+		WorkspaceName: workspaceName,
+		Bucket:        bucket,
+		Key:           stateKey,
+		Region:        m.config.AWS.Region,
+		DynamoDBTable: m.config.AWS.DynamoDBTable,
+		KMSKeyID:      m.config.AWS.KMSKeyID,
+	}
+}
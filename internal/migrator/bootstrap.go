@@ -0,0 +1,56 @@
+package migrator
+
+import (
+	"context"
+
+	"terraform-cloud-s3-migrator/internal/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Bootstrap cria o bucket de destino e os buckets de aws.environment_buckets, se ainda não existirem,
+// sem exigir acesso ao Terraform Cloud. Retorna true se algum bucket foi criado.
+func Bootstrap(ctx context.Context, cfg *config.Config) (bool, error) {
+	m, err := newStorageMigrator(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	created := false
+	for _, client := range m.destinations() {
+		manager, ok := client.(bucketManager)
+		if !ok {
+			m.logger.WithField("destination", sinkName(client)).Info("Destino não possui bucket a criar, pulando")
+			continue
+		}
+
+		ok, err := manager.EnsureBucket(ctx)
+		if err != nil {
+			return created, err
+		}
+		created = created || ok
+	}
+
+	return created, nil
+}
+
+// newStorageMigrator cria um Migrator apenas com os destinos no S3, para comandos que
+// não acessam o Terraform Cloud
+func newStorageMigrator(cfg *config.Config) (*Migrator, error) {
+	s3Client, err := newS3Client(cfg, cfg.AWS.Bucket, cfg.AWS.Prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	envSinks, err := newEnvironmentClients(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		sink:     s3Client,
+		envSinks: envSinks,
+		config:   cfg,
+		logger:   logrus.WithField("component", "migrator"),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package migrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// checkpoint registra os workspaces já migrados para permitir retomar execuções interrompidas.
+// Não é seguro para uso concorrente: chamadores devem proteger o acesso com um mutex.
+type checkpoint struct {
+	path       string
+	Workspaces map[string]checkpointEntry `json:"workspaces"`
+}
+
+type checkpointEntry struct {
+	Serial     int       `json:"serial"`
+	MigratedAt time.Time `json:"migrated_at"`
+}
+
+// loadCheckpoint carrega o checkpoint do arquivo, retornando um checkpoint vazio se ele não existir
+func loadCheckpoint(path string) (*checkpoint, error) {
+	cp := &checkpoint{
+		path:       path,
+		Workspaces: make(map[string]checkpointEntry),
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cp, nil
+		}
+		return nil, fmt.Errorf("erro ao ler checkpoint %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(content, cp); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar checkpoint %s: %w", path, err)
+	}
+
+	if cp.Workspaces == nil {
+		cp.Workspaces = make(map[string]checkpointEntry)
+	}
+
+	return cp, nil
+}
+
+// has indica se o workspace já foi registrado como migrado
+func (c *checkpoint) has(workspaceName string) bool {
+	_, ok := c.Workspaces[workspaceName]
+	return ok
+}
+
+// record registra o workspace como migrado e persiste o checkpoint no disco
+func (c *checkpoint) record(workspaceName string, serial int) error {
+	c.Workspaces[workspaceName] = checkpointEntry{
+		Serial:     serial,
+		MigratedAt: time.Now().UTC(),
+	}
+
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar checkpoint: %w", err)
+	}
+
+	if err := writeFileAtomic(c.path, content); err != nil {
+		return fmt.Errorf("erro ao gravar checkpoint: %w", err)
+	}
+
+	return nil
+}
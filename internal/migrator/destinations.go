@@ -0,0 +1,122 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"terraform-cloud-s3-migrator/internal/config"
+	"terraform-cloud-s3-migrator/internal/s3client"
+
+	"golang.org/x/time/rate"
+)
+
+// newEnvironmentClients cria um client do S3 para cada destino de aws.environment_buckets
+func newEnvironmentClients(cfg *config.Config, bandwidth *rate.Limiter) (map[string]StateSink, error) {
+	clients := make(map[string]StateSink, len(cfg.AWS.EnvironmentBuckets))
+	for suffix, destination := range cfg.AWS.EnvironmentBuckets {
+		prefix := destination.Prefix
+		if prefix == "" {
+			prefix = cfg.AWS.Prefix
+		}
+
+		client, err := newS3Client(cfg, destination.Bucket, prefix, bandwidth)
+		if err != nil {
+			return nil, fmt.Errorf("destino do sufixo %s: %w", suffix, err)
+		}
+		clients[strings.ToLower(suffix)] = client
+	}
+	return clients, nil
+}
+
+// destination retorna o destino do workspace. O sufixo de ambiente é detectado no nome
+// original, antes de ser removido; com mais de um sufixo compatível vence o mais longo.
+// Workspaces sem sufixo mapeado em aws.environment_buckets usam o bucket padrão.
+func (m *Migrator) destination(workspaceName string) StateSink {
+	name := strings.ToLower(workspaceName)
+
+	var matched string
+	for suffix := range m.envSinks {
+		if strings.HasSuffix(name, suffix) && len(suffix) > len(matched) {
+			matched = suffix
+		}
+	}
+
+	if matched == "" {
+		return m.sink
+	}
+	return m.envSinks[matched]
+}
+
+// destinations retorna o destino padrão seguido dos destinos por ambiente, em ordem de sufixo
+func (m *Migrator) destinations() []StateSink {
+	suffixes := make([]string, 0, len(m.envSinks))
+	for suffix := range m.envSinks {
+		suffixes = append(suffixes, suffix)
+	}
+	sort.Strings(suffixes)
+
+	clients := []StateSink{m.sink}
+	for _, suffix := range suffixes {
+		clients = append(clients, m.envSinks[suffix])
+	}
+	return clients
+}
+
+// artifactWriter retorna o destino do workspace como artifactWriter, ou erro se ele não grava os
+// arquivos exigidos pela opção informada
+func (m *Migrator) artifactWriter(workspaceName, option string) (artifactWriter, error) {
+	dest := m.destination(workspaceName)
+	writer, ok := dest.(artifactWriter)
+	if !ok {
+		return nil, unsupportedError(dest, option)
+	}
+	return writer, nil
+}
+
+// destinationState é um estado migrado junto do destino em que foi encontrado
+type destinationState struct {
+	s3client.StateObject
+	destination StateSink
+}
+
+// validateDestinations valida a conexão com o bucket padrão e com os buckets por ambiente
+func (m *Migrator) validateDestinations(ctx context.Context) error {
+	for _, dest := range m.destinations() {
+		if err := dest.ValidateConnection(ctx); err != nil {
+			return fmt.Errorf("falha na validação do S3 (bucket %s): %w", sinkName(dest), err)
+		}
+	}
+	return nil
+}
+
+// listStates lista os estados migrados em todos os destinos. Destinos que compartilham bucket e
+// prefixo retornam os mesmos objetos, que são considerados uma única vez.
+func (m *Migrator) listStates(ctx context.Context) ([]destinationState, error) {
+	var states []destinationState
+	seen := make(map[string]bool)
+
+	for _, dest := range m.destinations() {
+		lister, ok := dest.(stateLister)
+		if !ok {
+			return nil, unsupportedError(dest, "a listagem dos estados migrados")
+		}
+
+		objects, err := lister.ListStates(ctx, m.config.TerraformCloud.Organization)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar estados migrados no bucket %s: %w", sinkName(dest), err)
+		}
+
+		for _, st := range objects {
+			key := sinkName(dest) + "/" + st.StateKey
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			states = append(states, destinationState{StateObject: st, destination: dest})
+		}
+	}
+
+	return states, nil
+}
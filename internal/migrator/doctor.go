@@ -0,0 +1,83 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-cloud-s3-migrator/internal/config"
+)
+
+// DoctorCheck é o resultado de uma verificação do comando doctor
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Passed indica se a verificação foi bem-sucedida
+func (c DoctorCheck) Passed() bool {
+	return c.Error == ""
+}
+
+// newDoctorCheck cria o resultado de uma verificação a partir do erro retornado (nil indica sucesso)
+func newDoctorCheck(name, detail string, err error) DoctorCheck {
+	check := DoctorCheck{Name: name, Detail: detail}
+	if err != nil {
+		check.Error = err.Error()
+	}
+	return check
+}
+
+// Doctor verifica os pré-requisitos da migração: acesso ao Terraform Cloud e, em cada bucket de
+// destino, acesso na conta e região esperadas, versionamento, criptografia padrão e permissões de
+// escrita. Todas as verificações são executadas, mesmo após uma falha, exceto as de um bucket inacessível.
+// Em dry run nada é gravado: a verificação de escrita, que grava o objeto _healthcheck, é pulada.
+func Doctor(ctx context.Context, cfg *config.Config, dryRun bool) []DoctorCheck {
+	var checks []DoctorCheck
+
+	organization := cfg.TerraformCloud.Organization
+	tfClient, err := newTerraformClient(cfg)
+	if err == nil {
+		err = tfClient.ValidateConnection(ctx)
+	}
+	checks = append(checks, newDoctorCheck("Terraform Cloud: token e organização", organization, err))
+
+	m, err := newStorageMigrator(cfg)
+	if err != nil {
+		return append(checks, newDoctorCheck("S3: cliente AWS", "", err))
+	}
+
+	for _, dest := range m.destinations() {
+		bucket := sinkName(dest)
+
+		err := dest.ValidateConnection(ctx)
+		checks = append(checks, newDoctorCheck(fmt.Sprintf("S3 %s: acesso, conta e região", bucket), cfg.AWS.Region, err))
+		if err != nil {
+			continue
+		}
+
+		if manager, ok := dest.(bucketManager); ok {
+			enabled, status, err := manager.VersioningEnabled(ctx)
+			if err == nil && !enabled {
+				err = fmt.Errorf("versionamento do bucket S3 '%s' não está habilitado (status: %s); habilite-o antes da migração", bucket, status)
+			}
+			checks = append(checks, newDoctorCheck(fmt.Sprintf("S3 %s: versionamento", bucket), status, err))
+		}
+
+		if prober, ok := dest.(healthProber); ok {
+			encryption, err := prober.CheckEncryption(ctx)
+			checks = append(checks, newDoctorCheck(fmt.Sprintf("S3 %s: criptografia padrão", bucket), encryption, err))
+
+			name := fmt.Sprintf("S3 %s: permissões de escrita, leitura e remoção", bucket)
+			if dryRun {
+				checks = append(checks, newDoctorCheck(name, "pulada em dry run", nil))
+				continue
+			}
+
+			err = prober.ProbeWrite(ctx)
+			checks = append(checks, newDoctorCheck(name, "", err))
+		}
+	}
+
+	return checks
+}
@@ -0,0 +1,91 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCancelled indica que a migração foi cancelada na confirmação, antes de qualquer workspace ser processado
+var ErrCancelled = errors.New("migração cancelada: confirmação não recebida")
+
+// MigrationError agrega as falhas dos workspaces de uma execução do Migrate.
+// Use errors.As para obtê-lo e Errors para inspecionar cada falha.
+type MigrationError struct {
+	Failures []FailedMigration
+}
+
+func (e *MigrationError) Error() string {
+	return fmt.Sprintf("migração concluída com %d falhas", len(e.Failures))
+}
+
+// Errors retorna uma cópia das falhas registradas
+func (e *MigrationError) Errors() []FailedMigration {
+	return append([]FailedMigration(nil), e.Failures...)
+}
+
+// Unwrap retorna o erro original de cada workspace, permitindo errors.Is e errors.As nas falhas individuais
+func (e *MigrationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		if failure.Err != nil {
+			errs = append(errs, failure.Err)
+		}
+	}
+	return errs
+}
+
+// ConnectionError indica que a validação da conexão com o Terraform Cloud ou com o S3 falhou
+// antes de qualquer workspace ser processado
+type ConnectionError struct {
+	Err error
+}
+
+func (e *ConnectionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// Categorias de falha registradas em FailedMigration.Category, usadas para agrupar as falhas no relatório
+const (
+	CategoryDownload   = "download_failed"   // Falha ao obter o estado do Terraform Cloud
+	CategoryValidation = "validation_failed" // Conteúdo baixado não é um estado válido
+	CategoryUpload     = "upload_failed"     // Falha ao gravar no S3
+	CategoryOther      = "other"
+)
+
+// categoryError associa uma categoria de falha ao erro
+type categoryError struct {
+	category string
+	err      error
+}
+
+func (e *categoryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *categoryError) Unwrap() error {
+	return e.err
+}
+
+// withCategory associa a categoria ao erro (nil permanece nil)
+func withCategory(category string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &categoryError{category: category, err: err}
+}
+
+// failureCategory retorna a categoria mais interna do erro: uma falha de leitura do download
+// que interrompe o upload é classificada como download, não como upload
+func failureCategory(err error) string {
+	category := CategoryOther
+	var ce *categoryError
+	for errors.As(err, &ce) {
+		category = ce.category
+		err = ce.err
+	}
+	return category
+}
@@ -0,0 +1,171 @@
+package migrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"terraform-cloud-s3-migrator/internal/config"
+	"terraform-cloud-s3-migrator/internal/s3client"
+	"terraform-cloud-s3-migrator/internal/terraform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ExportStats resume o resultado de uma exportação para diretório local
+type ExportStats struct {
+	Exported   int               `json:"exported"`
+	Skipped    int               `json:"skipped"`
+	TotalBytes int64             `json:"total_bytes"`
+	Failed     []FailedMigration `json:"failed,omitempty"`
+}
+
+// Export baixa o estado e os metadados dos workspaces selecionados e os grava em outputDir,
+// usando o mesmo layout de chaves do S3. Nenhuma conexão com a AWS é feita.
+func Export(ctx context.Context, cfg *config.Config, options MigrationOptions, outputDir string) (*ExportStats, error) {
+	tfClient, err := newTerraformClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Migrator{
+		tfClient: tfClient,
+		config:   cfg,
+		logger:   logrus.WithField("component", "export"),
+	}
+
+	if err := m.tfClient.ValidateConnection(ctx); err != nil {
+		return nil, &ConnectionError{Err: fmt.Errorf("falha na validação do Terraform Cloud: %w", err)}
+	}
+
+	workspaces, err := m.selectWorkspaces(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := s3client.NewKeyLayout(s3client.Options{
+		Prefix:           cfg.AWS.Prefix,
+		AccountID:        cfg.AWS.AccountID,
+		KeyTemplate:      cfg.AWS.KeyTemplate,
+		StateFilename:    cfg.AWS.StateFilename,
+		MetadataFilename: cfg.AWS.MetadataFilename,
+	})
+
+	stats := &ExportStats{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, cfg.Migration.ConcurrentUploads)
+
+	for _, ws := range workspaces {
+		if !ws.HasState {
+			m.logger.WithField("workspace", ws.Name).Debug("Workspace sem estado, ignorando")
+			stats.Skipped++
+			continue
+		}
+
+		wg.Add(1)
+		go func(ws terraform.Workspace) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			size, err := m.exportWorkspace(ctx, ws, layout, outputDir)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				m.logger.WithError(err).WithField("workspace", ws.Name).Error("Falha ao exportar workspace")
+				stats.Failed = append(stats.Failed, FailedMigration{
+					WorkspaceName: ws.Name,
+					Category:      failureCategory(err),
+					Error:         err.Error(),
+					Err:           err,
+				})
+				return
+			}
+
+			stats.Exported++
+			stats.TotalBytes += size
+		}(ws)
+	}
+
+	wg.Wait()
+
+	m.logger.WithFields(logrus.Fields{
+		"exported": stats.Exported,
+		"skipped":  stats.Skipped,
+		"failed":   len(stats.Failed),
+		"bytes":    stats.TotalBytes,
+	}).Info("Exportação concluída")
+
+	if len(stats.Failed) > 0 {
+		return stats, &MigrationError{Failures: stats.Failed}
+	}
+
+	return stats, nil
+}
+
+// exportWorkspace grava o estado e o metadata.json de um workspace em outputDir
+func (m *Migrator) exportWorkspace(ctx context.Context, ws terraform.Workspace, layout s3client.KeyLayout, outputDir string) (int64, error) {
+	stateData, err := m.tfClient.GetWorkspaceState(ctx, ws.ID)
+	if err != nil {
+		return 0, withCategory(CategoryDownload, fmt.Errorf("erro ao obter estado: %w", err))
+	}
+
+	identity, err := validateState(bytes.NewReader(stateData.StateContent))
+	if err != nil {
+		return 0, withCategory(CategoryValidation, err)
+	}
+	identity.record(stateData.Metadata)
+
+	organization := m.config.TerraformCloud.Organization
+	stateName := m.s3Name(ws.Name)
+
+	metadata := make(map[string]interface{}, len(stateData.Metadata)+1)
+	for key, value := range stateData.Metadata {
+		metadata[key] = value
+	}
+	metadata["has_state"] = true
+
+	metadataJSON, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("erro ao serializar metadados: %w", err)
+	}
+
+	statePath := filepath.Join(outputDir, filepath.FromSlash(layout.StatePath(organization, stateName)))
+	if err := writeExportFile(statePath, stateData.StateContent); err != nil {
+		return 0, err
+	}
+
+	metadataPath := filepath.Join(outputDir, filepath.FromSlash(layout.MetadataPath(organization, stateName)))
+	if err := writeExportFile(metadataPath, metadataJSON); err != nil {
+		return 0, err
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"workspace": ws.Name,
+		"path":      statePath,
+	}).Info("Estado exportado")
+
+	return int64(len(stateData.StateContent)), nil
+}
+
+// writeExportFile grava content em path criando os diretórios intermediários.
+// Estados podem conter segredos, por isso as permissões são restritas ao usuário.
+func writeExportFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("erro ao criar diretório %s: %w", filepath.Dir(path), err)
+	}
+
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package migrator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"terraform-cloud-s3-migrator/internal/config"
+	"terraform-cloud-s3-migrator/internal/terraform"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestMain(m *testing.M) {
+	logrus.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// fakeSource é uma origem em memória que implementa apenas o TerraformSource
+type fakeSource struct {
+	workspaces []terraform.Workspace
+	states     map[string][]byte // Conteúdo do estado por ID do workspace
+}
+
+func (s *fakeSource) ListWorkspaces(ctx context.Context, filter terraform.WorkspaceFilter) ([]terraform.Workspace, error) {
+	return s.workspaces, nil
+}
+
+func (s *fakeSource) GetWorkspaceState(ctx context.Context, workspaceID string) (*terraform.StateData, error) {
+	for _, ws := range s.workspaces {
+		if ws.ID == workspaceID {
+			// A versão no Terraform Cloud é o serial do próprio conteúdo
+			var header struct {
+				Serial int `json:"serial"`
+			}
+			_ = json.Unmarshal(s.states[workspaceID], &header)
+
+			return &terraform.StateData{
+				WorkspaceName: ws.Name,
+				StateContent:  s.states[workspaceID],
+				Size:          int64(len(s.states[workspaceID])),
+				Version:       header.Serial,
+				Metadata:      map[string]interface{}{"workspace_name": ws.Name},
+			}, nil
+		}
+	}
+	return nil, terraform.ErrWorkspaceNotFound
+}
+
+func (s *fakeSource) GetWorkspaceByName(ctx context.Context, name string) (*terraform.Workspace, error) {
+	for _, ws := range s.workspaces {
+		if ws.Name == name {
+			return &ws, nil
+		}
+	}
+	return nil, terraform.ErrWorkspaceNotFound
+}
+
+func (s *fakeSource) ValidateConnection(ctx context.Context) error {
+	return nil
+}
+
+// fakeSink é um destino em memória que implementa apenas o StateSink
+type fakeSink struct {
+	mu     sync.Mutex
+	states map[string][]byte // Conteúdo enviado por nome do workspace no destino
+
+	// Erro retornado por todos os uploads, se definido
+	uploadErr error
+}
+
+func (s *fakeSink) UploadState(ctx context.Context, organization, workspaceName string, body io.Reader, metadata map[string]interface{}) error {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if s.uploadErr != nil {
+		return s.uploadErr
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.states == nil {
+		s.states = make(map[string][]byte)
+	}
+	s.states[workspaceName] = content
+	return nil
+}
+
+func (s *fakeSink) CheckStateExists(ctx context.Context, organization, workspaceName string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.states[workspaceName]
+	return ok, nil
+}
+
+func (s *fakeSink) DeleteState(ctx context.Context, organization, workspaceName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, workspaceName)
+	return nil
+}
+
+func (s *fakeSink) ValidateConnection(ctx context.Context) error {
+	return nil
+}
+
+// newTestMigrator cria um Migrator com a origem e o destino em memória
+func newTestMigrator(t *testing.T, source *fakeSource, sink *fakeSink) *Migrator {
+	t.Helper()
+
+	cfg := &config.Config{
+		TerraformCloud: config.TerraformCloudConfig{Organization: "acme"},
+		Migration: config.MigrationConfig{
+			BatchSize:         5,
+			ConcurrentUploads: 2,
+			ScanConcurrency:   2,
+			StripSuffixes:     true,
+		},
+	}
+
+	m, err := NewMigrator(cfg, WithTerraformSource(source), WithStateSink(sink))
+	if err != nil {
+		t.Fatalf("erro ao criar migrator: %v", err)
+	}
+	return m
+}
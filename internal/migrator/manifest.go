@@ -0,0 +1,153 @@
+package migrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"terraform-cloud-s3-migrator/internal/s3client"
+)
+
+// manifest mapeia os workspaces do Terraform Cloud para as chaves no S3 e é a referência
+// usada pelos comandos verify, rollback e generate-backend.
+// Não é seguro para uso concorrente: chamadores devem proteger o acesso com um mutex.
+type manifest struct {
+	Organization string                   `json:"organization"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+	Workspaces   map[string]ManifestEntry `json:"workspaces"`
+}
+
+// ManifestEntry descreve onde o estado de um workspace foi gravado no S3
+type ManifestEntry struct {
+	Workspace  string    `json:"workspace"`
+	S3Name     string    `json:"s3_name"`
+	Bucket     string    `json:"bucket,omitempty"` // Vazio em manifestos anteriores: aws.bucket
+	StateKey   string    `json:"state_key"`
+	Serial     int       `json:"serial"`
+	MigratedAt time.Time `json:"migrated_at"`
+}
+
+// loadManifest carrega o manifesto do arquivo local ou, se não existir e o upload estiver habilitado, do S3.
+// Retorna um manifesto vazio se nenhum for encontrado.
+func (m *Migrator) loadManifest(ctx context.Context) (*manifest, error) {
+	mf := &manifest{
+		Organization: m.config.TerraformCloud.Organization,
+		Workspaces:   make(map[string]ManifestEntry),
+	}
+
+	var content []byte
+	if path := m.config.Migration.ManifestFile; path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("erro ao ler manifesto %s: %w", path, err)
+		}
+		content = data
+	}
+
+	if content == nil && m.config.Migration.UploadManifest {
+		store, ok := m.sink.(manifestStore)
+		if !ok {
+			return nil, unsupportedError(m.sink, "o manifesto remoto (migration.upload_manifest)")
+		}
+
+		data, err := store.DownloadManifest(ctx, m.config.TerraformCloud.Organization)
+		if err != nil && !errors.Is(err, s3client.ErrManifestNotFound) {
+			return nil, err
+		}
+		content = data
+	}
+
+	if content == nil {
+		return mf, nil
+	}
+
+	if err := json.Unmarshal(content, mf); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar manifesto: %w", err)
+	}
+
+	if mf.Workspaces == nil {
+		mf.Workspaces = make(map[string]ManifestEntry)
+	}
+
+	return mf, nil
+}
+
+// saveManifest grava o manifesto no arquivo local e, se habilitado, no S3
+func (m *Migrator) saveManifest(ctx context.Context, mf *manifest) error {
+	mf.UpdatedAt = time.Now().UTC()
+
+	content, err := json.MarshalIndent(mf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar manifesto: %w", err)
+	}
+
+	if path := m.config.Migration.ManifestFile; path != "" {
+		if err := writeFileAtomic(path, content); err != nil {
+			return fmt.Errorf("erro ao gravar manifesto: %w", err)
+		}
+	}
+
+	if m.config.Migration.UploadManifest {
+		store, ok := m.sink.(manifestStore)
+		if !ok {
+			return unsupportedError(m.sink, "o manifesto remoto (migration.upload_manifest)")
+		}
+		if err := store.UploadManifest(ctx, m.config.TerraformCloud.Organization, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lookup retorna a entrada do workspace pelo nome original
+func (mf *manifest) lookup(workspaceName string) (ManifestEntry, bool) {
+	entry, ok := mf.Workspaces[workspaceName]
+	return entry, ok
+}
+
+// record registra o workspace migrado no manifesto
+func (mf *manifest) record(entry ManifestEntry) {
+	mf.Workspaces[entry.Workspace] = entry
+}
+
+// removeS3Name remove as entradas gravadas com o nome informado no S3
+func (mf *manifest) removeS3Name(s3Name string) {
+	for name, entry := range mf.Workspaces {
+		if entry.S3Name == s3Name {
+			delete(mf.Workspaces, name)
+		}
+	}
+}
+
+// writeFileAtomic grava o conteúdo em um arquivo temporário e o renomeia, para não corromper
+// o arquivo em caso de falha
+func writeFileAtomic(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de %s: %w", path, err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("erro ao criar arquivo temporário: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("erro ao gravar %s: %w", path, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", path, err)
+	}
+
+	return nil
+}
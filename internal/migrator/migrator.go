@@ -2,76 +2,261 @@ package migrator
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"terraform-cloud-s3-migrator/internal/config"
+	"terraform-cloud-s3-migrator/internal/retry"
 	"terraform-cloud-s3-migrator/internal/s3client"
 	"terraform-cloud-s3-migrator/internal/terraform"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 )
 
 type Migrator struct {
-	tfClient *terraform.Client
-	s3Client *s3client.Client
-	config   *config.Config
-	logger   *logrus.Entry
+	tfClient   TerraformSource
+	sink       StateSink
+	envSinks   map[string]StateSink // Destinos de aws.environment_buckets, por sufixo em minúsculas
+	config     *config.Config
+	logger     *logrus.Entry
+	checkpoint *checkpoint
+	manifest   *manifest
+	audit      *auditLog
+	progress   *progress
+
+	// Workspaces cujo nome normalizado já foi registrado no log
+	normalizedNames sync.Map
 }
 
 type MigrationOptions struct {
-	DryRun   bool
-	Projects []string
+	DryRun     bool
+	Projects   []string
+	Exclude    []string // Workspaces removidos da seleção, mesmo se listados em Projects
+	Regex      bool     // Interpreta Projects e Exclude como expressões regulares em vez de globs
+	Tags       []string // Seleciona apenas workspaces que possuem todas as tags
+	TFCProject string   // Seleciona apenas workspaces do projeto do Terraform Cloud com este nome
+	Force      bool     // Ignora o checkpoint e reprocessa workspaces já registrados
+	Strict     bool     // Falha se algum workspace pedido pelo nome em Projects não existir ou não tiver estado
+	Overwrite  bool     // Reenvia estados já existentes no S3 cujo serial mudou no Terraform Cloud
+
+	// Grava o digest de cada estado na tabela DynamoDB de lock do backend S3
+	CreateLockEntries bool
+
+	// Migra também as versões anteriores do estado para a pasta de histórico
+	IncludeHistory bool
+
+	// Grava as variáveis do workspace em variables.json ao lado do estado (valores sensíveis omitidos)
+	IncludeVariables bool
+
+	// Não valida se o conteúdo baixado é um estado do Terraform antes do upload
+	SkipValidation bool
+
+	// Remove do estado o bloco "backend" que aponta para o Terraform Cloud (remote/cloud) antes do upload.
+	// Sem a opção o bloco é mantido e apenas um alerta é registrado.
+	RewriteBackend bool
+
+	// Falha se o versionamento do bucket não estiver habilitado, em vez de apenas alertar
+	RequireVersioning bool
+
+	// Interrompe o agendamento de novos workspaces quando o número de falhas ultrapassar o limite (zero desativa)
+	MaxFailures int
+
+	// Interrompe o agendamento de novos workspaces na primeira falha de autenticação ou permissão
+	FailFast bool
+
+	// Grava apenas o metadata.json (has_state: false) dos workspaces sem estado, em vez de ignorá-los
+	IncludeNoState bool
+
+	// Em verify, compara serial, lineage e endereços de recursos dos estados divergentes
+	Diff bool
+
+	// Ignora workspaces cuja versão atual do estado foi criada antes desta data (zero desativa)
+	Since time.Time
+
+	// Ignora workspaces cuja versão atual do estado foi criada após esta data, por estarem em uso (zero desativa)
+	RecentCutoff time.Time
+
+	// Pula workspaces travados, que podem estar no meio de um apply com o serial prestes a mudar
+	SkipLocked bool
+
+	// Seleciona apenas workspaces travados, para migrar deliberadamente workspaces congelados
+	OnlyLocked bool
+
+	// Destino da linha de progresso atualizada a cada workspace (nil desativa)
+	Progress io.Writer
+
+	// Arquivo reescrito periodicamente com o snapshot das estatísticas em JSON (vazio desativa)
+	ProgressFile string
+
+	// Chamado antes de uma migração real com o total de workspaces a migrar; retornar false cancela
+	// a execução (nil não pede confirmação)
+	Confirm func(total int) (bool, error)
 }
 
 type MigrationStats struct {
-	Total       int
-	Successful  int
-	Failed      int
-	StartTime   time.Time
-	EndTime     time.Time
-	Duration    time.Duration
-	FailedItems []FailedMigration
+	Total            int
+	Successful       int
+	Failed           int
+	StartTime        time.Time
+	EndTime          time.Time
+	Duration         time.Duration
+	FailedItems      []FailedMigration
+	Collisions       []KeyCollision
+	SkippedNoState   []string    // Workspaces pedidos pelo nome em Projects que não possuem estado
+	SkippedTooOld    []string    // Workspaces com estado anterior a MigrationOptions.Since
+	SkippedRecent    []string    // Workspaces com estado posterior a MigrationOptions.RecentCutoff
+	SkippedLocked    []string    // Workspaces travados pulados por MigrationOptions.SkipLocked
+	Skipped          int         // Workspaces selecionados que não serão migrados (sem estado, já migrados, colisões...)
+	Interrupted      bool        // A execução foi interrompida antes de processar todos os workspaces
+	Aborted          bool        // A execução foi abortada por ultrapassar MaxFailures ou por FailFast
+	AbortReason      string      // Motivo do abort, exibido no erro retornado e no relatório
+	Plan             []PlanEntry // Em dry run, a ação prevista no S3 para cada workspace com estado
+	WorkspaceResults []WorkspaceResult
+}
+
+type KeyCollision struct {
+	S3Name     string   `json:"s3_name"`
+	Workspaces []string `json:"workspaces"`
 }
 
 type FailedMigration struct {
+	WorkspaceName string `json:"workspace"`
+	Category      string `json:"category"` // download_failed, validation_failed, upload_failed ou other
+	Error         string `json:"error"`
+	Err           error  `json:"-"` // Erro original, exposto por MigrationError.Unwrap
+}
+
+// WorkspaceResult registra o resultado da migração de um workspace, com ou sem sucesso
+type WorkspaceResult struct {
 	WorkspaceName string
+	S3Name        string
+	Success       bool
+	Duration      time.Duration
+	Bytes         int64
+	Serial        int
 	Error         string
 }
 
-// NewMigrator cria uma nova instância do migrator
-func NewMigrator(cfg *config.Config) (*Migrator, error) {
-	// Criar client do Terraform Cloud
-	tfClient, err := terraform.NewClient(cfg.TerraformCloud.Token, cfg.TerraformCloud.Organization)
+// NewMigrator cria uma nova instância do migrator. Sem WithTerraformSource é usado o client
+// do Terraform Cloud da configuração.
+func NewMigrator(cfg *config.Config, opts ...Option) (*Migrator, error) {
+	logger := logrus.WithField("component", "migrator")
+
+	m := &Migrator{
+		config: cfg,
+		logger: logger,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	// O limite de banda é compartilhado por todos os buckets de destino
+	bandwidth := s3client.NewBandwidthLimiter(cfg.Migration.MaxBandwidthMbps)
+
+	if m.sink == nil {
+		s3Client, err := newS3Client(cfg, cfg.AWS.Bucket, cfg.AWS.Prefix, bandwidth)
+		if err != nil {
+			return nil, err
+		}
+		m.sink = s3Client
+	}
+
+	envSinks, err := newEnvironmentClients(cfg, bandwidth)
+	if err != nil {
+		return nil, err
+	}
+	m.envSinks = envSinks
+
+	if m.tfClient == nil {
+		tfClient, err := newTerraformClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		m.tfClient = tfClient
+	}
+
+	return m, nil
+}
+
+// newTerraformClient cria o client do Terraform Cloud a partir da configuração
+func newTerraformClient(cfg *config.Config) (*terraform.Client, error) {
+	tfClient, err := terraform.NewClient(terraform.Options{
+		Token:             cfg.TerraformCloud.Token,
+		Organization:      cfg.TerraformCloud.Organization,
+		Address:           cfg.TerraformCloud.Address,
+		RequestsPerSecond: cfg.Migration.RequestsPerSecond,
+		DownloadTimeout:   cfg.Migration.DownloadTimeout,
+		MaxStateSize:      cfg.Migration.MaxStateSizeMB * 1024 * 1024,
+		Backoff: retry.Backoff{
+			Attempts:  cfg.Migration.RetryAttempts,
+			BaseDelay: cfg.Migration.RetryBaseDelay,
+			MaxDelay:  cfg.Migration.RetryMaxDelay,
+		},
+	})
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar client do Terraform Cloud: %w", err)
 	}
 
-	// Criar client do S3
-	s3Client, err := s3client.NewClient(cfg.AWS.Region, cfg.AWS.Bucket, cfg.AWS.Prefix, cfg.AWS.Profile, cfg.AWS.AccountID)
+	return tfClient, nil
+}
+
+// newS3Client cria o client do S3 para o bucket e prefixo informados, com as demais opções da configuração
+func newS3Client(cfg *config.Config, bucket, prefix string, bandwidth *rate.Limiter) (*s3client.Client, error) {
+	s3Client, err := s3client.NewClient(s3client.Options{
+		Region:        cfg.AWS.Region,
+		Bucket:        bucket,
+		Prefix:        prefix,
+		Profile:       cfg.AWS.Profile,
+		AccountID:     cfg.AWS.AccountID,
+		KMSKeyID:      cfg.AWS.KMSKeyID,
+		Compress:      cfg.Migration.Compress,
+		KeyTemplate:   cfg.AWS.KeyTemplate,
+		DynamoDBTable: cfg.AWS.DynamoDBTable,
+
+		StateFilename:    cfg.AWS.StateFilename,
+		MetadataFilename: cfg.AWS.MetadataFilename,
+
+		UploadPartSizeMB:  cfg.AWS.UploadPartSizeMB,
+		UploadConcurrency: cfg.AWS.UploadConcurrency,
+		ObjectTags:        cfg.AWS.ObjectTags,
+		ExtraMetadata:     cfg.Migration.ExtraMetadata,
+		StorageClass:      cfg.AWS.StorageClass,
+		Bandwidth:         bandwidth,
+		EndpointURL:       cfg.AWS.EndpointURL,
+		RoleARN:           cfg.AWS.RoleARN,
+		ExternalID:        cfg.AWS.ExternalID,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar client do S3: %w", err)
 	}
 
-	logger := logrus.WithField("component", "migrator")
-
-	return &Migrator{
-		tfClient: tfClient,
-		s3Client: s3Client,
-		config:   cfg,
-		logger:   logger,
-	}, nil
+	return s3Client, nil
 }
 
+// defaultEnvironmentSuffixes é a lista de sufixos usada quando migration.environment_suffixes não é configurado
+var defaultEnvironmentSuffixes = []string{"-stg", "-prd", "-dev", "-prod", "-staging", "-production", "-test", "-qa", "-uat"}
+
 // removeEnvironmentSuffix remove sufixos comuns de ambiente do nome do workspace
 func (m *Migrator) removeEnvironmentSuffix(workspaceName string) string {
-	// Lista de sufixos de ambiente comuns
-	envSuffixes := []string{"-stg", "-prd", "-dev", "-prod", "-staging", "-production", "-test", "-qa", "-uat"}
+	if !m.config.Migration.StripSuffixes {
+		return workspaceName
+	}
+
+	envSuffixes := m.config.Migration.EnvironmentSuffixes
+	if len(envSuffixes) == 0 {
+		envSuffixes = defaultEnvironmentSuffixes
+	}
 
 	for _, suffix := range envSuffixes {
-		if strings.HasSuffix(strings.ToLower(workspaceName), suffix) {
+		if strings.HasSuffix(strings.ToLower(workspaceName), strings.ToLower(suffix)) {
 			cleanName := workspaceName[:len(workspaceName)-len(suffix)]
 			m.logger.WithFields(logrus.Fields{
 				"original_name":  workspaceName,
@@ -87,140 +272,358 @@ func (m *Migrator) removeEnvironmentSuffix(workspaceName string) string {
 }
 
 // ValidateConnections valida as conexões com Terraform Cloud e S3
-func (m *Migrator) ValidateConnections() error {
-	ctx := context.Background()
-
+func (m *Migrator) ValidateConnections(ctx context.Context) error {
 	m.logger.Info("Validando conexões...")
 
 	// Validar Terraform Cloud
 	if err := m.tfClient.ValidateConnection(ctx); err != nil {
-		return fmt.Errorf("falha na validação do Terraform Cloud: %w", err)
+		return &ConnectionError{Err: fmt.Errorf("falha na validação do Terraform Cloud: %w", err)}
 	}
 
-	// Validar S3
-	if err := m.s3Client.ValidateConnection(ctx); err != nil {
-		return fmt.Errorf("falha na validação do S3: %w", err)
+	// Validar S3, incluindo os buckets por ambiente
+	for _, client := range m.destinations() {
+		if err := client.ValidateConnection(ctx); err != nil {
+			return &ConnectionError{Err: fmt.Errorf("falha na validação do S3 (bucket %s): %w", sinkName(client), err)}
+		}
 	}
 
 	m.logger.Info("Todas as conexões validadas com sucesso")
 	return nil
 }
 
+// checkVersioning alerta, ou falha se required, quando o versionamento de algum bucket de destino
+// não está habilitado. Sem versionamento uma sobrescrita acidental do estado não pode ser desfeita.
+func (m *Migrator) checkVersioning(ctx context.Context, required bool) error {
+	for _, client := range m.destinations() {
+		if err := m.checkBucketVersioning(ctx, client, required); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkBucketVersioning verifica o versionamento do bucket de um client
+func (m *Migrator) checkBucketVersioning(ctx context.Context, client StateSink, required bool) error {
+	manager, ok := client.(bucketManager)
+	if !ok {
+		if required {
+			return unsupportedError(client, "a verificação de versionamento (--require-versioning)")
+		}
+		m.logger.WithField("bucket", sinkName(client)).Debug("Destino não informa o versionamento, verificação ignorada")
+		return nil
+	}
+
+	enabled, status, err := manager.VersioningEnabled(ctx)
+	if err != nil {
+		if required {
+			return err
+		}
+		m.logger.WithError(err).WithField("bucket", sinkName(client)).Warn("Não foi possível verificar o versionamento do bucket S3")
+		return nil
+	}
+
+	if enabled {
+		return nil
+	}
+
+	if required {
+		return fmt.Errorf("versionamento do bucket S3 '%s' não está habilitado (status: %s); habilite-o ou execute sem --require-versioning", sinkName(client), status)
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"bucket":     sinkName(client),
+		"versioning": status,
+	}).Warn("ATENÇÃO: versionamento do bucket S3 não está habilitado; uma sobrescrita acidental dos estados não poderá ser desfeita")
+	return nil
+}
+
 // ListWorkspaces lista todos os workspaces disponíveis
-func (m *Migrator) ListWorkspaces() ([]terraform.Workspace, error) {
-	ctx := context.Background()
+func (m *Migrator) ListWorkspaces(ctx context.Context) ([]terraform.Workspace, error) {
+	if err := m.ValidateConnections(ctx); err != nil {
+		return nil, err
+	}
+
+	return m.tfClient.ListWorkspaces(ctx, terraform.WorkspaceFilter{})
+}
 
-	if err := m.ValidateConnections(); err != nil {
+// ListWorkspacesPage lista apenas uma página de workspaces, para organizações muito grandes
+func (m *Migrator) ListWorkspacesPage(ctx context.Context, page, size int) (*terraform.WorkspacePage, error) {
+	if err := m.ValidateConnections(ctx); err != nil {
 		return nil, err
 	}
 
-	return m.tfClient.ListWorkspaces(ctx)
+	pager, ok := m.tfClient.(workspacePager)
+	if !ok {
+		return nil, m.unsupportedSourceError("a listagem paginada (--page)")
+	}
+	return pager.ListWorkspacesPage(ctx, page, size)
 }
 
-// Migrate executa a migração dos estados
-func (m *Migrator) Migrate(options MigrationOptions) error {
-	ctx := context.Background()
+// Migrate executa a migração dos estados e retorna as estatísticas da execução.
+// Quando ctx é cancelado, nenhum novo workspace é iniciado e os que estão em andamento são concluídos.
+func (m *Migrator) Migrate(ctx context.Context, options MigrationOptions) (*MigrationStats, error) {
+	if options.CreateLockEntries && m.config.AWS.DynamoDBTable == "" {
+		return nil, fmt.Errorf("--create-lock-entries requer aws.dynamodb_table configurado")
+	}
+	// O digest é o MD5 do estado descomprimido, que o backend S3 compara com o objeto que ele lê;
+	// um estado .gz não é lido pelo backend, então a entrada nunca corresponderia ao objeto gravado
+	if options.CreateLockEntries && m.config.Migration.Compress {
+		return nil, fmt.Errorf("--create-lock-entries não pode ser combinado com migration.compress: o backend S3 não lê estados comprimidos")
+	}
+	if options.CreateLockEntries {
+		for _, dest := range m.destinations() {
+			if _, ok := dest.(lockWriter); !ok {
+				return nil, unsupportedError(dest, "--create-lock-entries")
+			}
+		}
+	}
 
 	// Validar conexões antes de iniciar
-	if err := m.ValidateConnections(); err != nil {
-		return err
+	if err := m.ValidateConnections(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkVersioning(ctx, options.RequireVersioning); err != nil {
+		return nil, err
+	}
+
+	// O backend S3 do Terraform lê o objeto como JSON puro; estados .gz precisam ser descomprimidos antes do uso
+	if m.config.Migration.Compress {
+		m.logger.Warn("migration.compress ativado: os estados gravados em .gz não podem ser lidos diretamente pelo backend S3 do Terraform")
 	}
 
 	stats := &MigrationStats{
 		StartTime: time.Now(),
 	}
 
+	// Carregar checkpoint de execuções anteriores
+	m.checkpoint = nil
+	if path := m.config.Migration.CheckpointFile; path != "" {
+		cp, err := loadCheckpoint(path)
+		if err != nil {
+			return nil, err
+		}
+		if options.Force && len(cp.Workspaces) > 0 {
+			m.logger.WithField("checkpoint", path).Info("Flag --force ativada, ignorando workspaces registrados no checkpoint")
+		}
+		m.checkpoint = cp
+	}
+
+	// O audit log registra apenas migrações reais
+	m.audit = nil
+	if path := m.config.Migration.AuditLog; path != "" && !options.DryRun {
+		audit, err := openAuditLog(path)
+		if err != nil {
+			return nil, err
+		}
+		defer audit.close()
+		m.audit = audit
+	}
+
+	// Carregar o manifesto para acrescentar os workspaces migrados nesta execução
+	m.manifest = nil
+	if !options.DryRun {
+		mf, err := m.loadManifest(ctx)
+		if err != nil {
+			return nil, err
+		}
+		m.manifest = mf
+	}
+
 	// Obter lista de workspaces para migrar
-	workspaces, err := m.getWorkspacesToMigrate(ctx, options.Projects)
+	workspaces, err := m.getWorkspacesToMigrate(ctx, options, stats)
 	if err != nil {
-		return fmt.Errorf("erro ao obter lista de workspaces: %w", err)
+		return nil, fmt.Errorf("erro ao obter lista de workspaces: %w", err)
 	}
 
 	stats.Total = len(workspaces)
 
+	if options.Strict && len(stats.SkippedNoState) > 0 {
+		stats.EndTime = time.Now()
+		stats.Duration = stats.EndTime.Sub(stats.StartTime)
+		m.writeProgressFile(options.ProgressFile, stats)
+		return stats, fmt.Errorf("workspaces solicitados sem estado do Terraform (--strict): %s", strings.Join(stats.SkippedNoState, ", "))
+	}
+
 	if stats.Total == 0 {
 		m.logger.Warn("Nenhum workspace encontrado para migração")
-		return nil
+		stats.EndTime = time.Now()
+		stats.Duration = stats.EndTime.Sub(stats.StartTime)
+		// Colisões e workspaces pulados explicam por que não há nada a migrar
+		m.writeProgressFile(options.ProgressFile, stats)
+		m.logFinalStats(stats, options.DryRun)
+		return stats, nil
 	}
 
-	m.logger.WithFields(logrus.Fields{
+	if options.Confirm != nil && !options.DryRun {
+		confirmed, err := options.Confirm(stats.Total)
+		if err != nil {
+			return stats, fmt.Errorf("erro ao pedir confirmação: %w", err)
+		}
+		if !confirmed {
+			return stats, ErrCancelled
+		}
+	}
+
+	fields := logrus.Fields{
 		"total_workspaces": stats.Total,
-		"batch_size":       m.config.Migration.BatchSize,
+		"workers":          m.config.Migration.ConcurrentUploads,
 		"dry_run":          options.DryRun,
-	}).Info("Iniciando migração")
+	}
 
-	// Processar em batches
-	err = m.processBatches(ctx, workspaces, options, stats)
-	if err != nil {
-		return err
+	// A estimativa usa apenas os tamanhos informados pela API, sem baixar os estados
+	estimate := m.estimateMigration(ctx, workspaces)
+	if estimate != nil {
+		fields["estimated_bytes"] = estimate.TotalBytes
+		fields["eta"] = estimate.ETA.String()
+		fields["suggested_workers"] = estimate.SuggestedWorkers
+	}
+
+	m.logger.WithFields(fields).Info("Iniciando migração")
+
+	if estimate != nil && estimate.SuggestedWorkers != m.config.Migration.ConcurrentUploads {
+		m.logger.WithFields(logrus.Fields{
+			"sampled":           estimate.Sampled,
+			"average_bytes":     estimate.AverageBytes,
+			"suggested_workers": estimate.SuggestedWorkers,
+		}).Infof("Sugestão: --concurrent-uploads %d, conforme o tamanho médio dos estados e os limites de requisições e banda", estimate.SuggestedWorkers)
+	}
+
+	// Processar todos os workspaces no pool de workers
+	m.progress = newProgress(options.Progress, stats.Total)
+	m.processWorkspaces(ctx, workspaces, options, stats)
+	m.progress.finish()
+
+	if m.manifest != nil && stats.Successful > 0 {
+		if err := m.saveManifest(context.WithoutCancel(ctx), m.manifest); err != nil {
+			m.logger.WithError(err).Error("Erro ao gravar manifesto da migração")
+		}
 	}
 
 	// Calcular estatísticas finais
 	stats.EndTime = time.Now()
 	stats.Duration = stats.EndTime.Sub(stats.StartTime)
 
+	stats.Interrupted = ctx.Err() != nil
+
+	m.writeProgressFile(options.ProgressFile, stats)
 	m.logFinalStats(stats, options.DryRun)
 
+	if stats.Aborted {
+		return stats, fmt.Errorf("migração abortada após %d falhas (%s): %d de %d workspaces processados", stats.Failed, stats.AbortReason, stats.Successful+stats.Failed, stats.Total)
+	}
+
+	if stats.Interrupted {
+		return stats, fmt.Errorf("migração interrompida (%v): %d de %d workspaces processados", context.Cause(ctx), stats.Successful+stats.Failed, stats.Total)
+	}
+
 	if stats.Failed > 0 {
-		return fmt.Errorf("migração concluída com %d falhas", stats.Failed)
+		// A cópia evita que o erro compartilhe o slice com as estatísticas retornadas
+		return stats, &MigrationError{Failures: append([]FailedMigration(nil), stats.FailedItems...)}
 	}
 
-	return nil
+	return stats, nil
 }
 
 // getWorkspacesToMigrate obtém a lista de workspaces para migrar
-func (m *Migrator) getWorkspacesToMigrate(ctx context.Context, projectFilter []string) ([]terraform.Workspace, error) {
-	var workspaces []terraform.Workspace
-	var notFoundProjects []string
-
-	if len(projectFilter) > 0 {
-		// Migrar apenas projetos específicos
-		m.logger.WithField("projects", projectFilter).Info("Migrando projetos específicos")
-		for _, projectName := range projectFilter {
-			workspace, err := m.tfClient.GetWorkspaceByName(ctx, projectName)
-			if err != nil {
-				m.logger.WithField("workspace", projectName).Warn("Workspace não encontrado")
-				notFoundProjects = append(notFoundProjects, projectName)
-				continue
-			}
-			workspaces = append(workspaces, *workspace)
-		}
+func (m *Migrator) getWorkspacesToMigrate(ctx context.Context, options MigrationOptions, stats *MigrationStats) ([]terraform.Workspace, error) {
+	workspaces, err := m.selectWorkspaces(ctx, options)
+	if err != nil {
+		return nil, err
+	}
 
-		if len(notFoundProjects) > 0 {
-			m.logger.WithField("not_found", notFoundProjects).Warn("Alguns projetos especificados não foram encontrados")
-		}
-	} else {
-		// Migrar todos os workspaces
-		m.logger.Info("Migrando TODOS os workspaces da organização")
-		allWorkspaces, err := m.tfClient.ListWorkspaces(ctx)
-		if err != nil {
-			return nil, err
-		}
-		workspaces = allWorkspaces
+	requested := make(map[string]bool, len(options.Projects))
+	for _, name := range options.Projects {
+		requested[name] = true
 	}
 
 	// Filtrar e contar workspaces por estado
+	var candidates []terraform.Workspace
 	var workspacesWithState []terraform.Workspace
 	var workspacesWithoutState []string
 	var existingStates []string
+	var checkpointed []string
+	var unlocked []string
 
 	for _, ws := range workspaces {
-		if !ws.HasState {
-			m.logger.WithField("workspace", ws.Name).Debug("Workspace sem estado do Terraform, pulando")
+		if !ws.HasState && !options.IncludeNoState {
+			if requested[ws.Name] {
+				m.logger.WithField("workspace", ws.Name).Warn("Workspace solicitado em --projects não possui estado do Terraform e não será migrado")
+				stats.SkippedNoState = append(stats.SkippedNoState, ws.Name)
+			} else {
+				m.logger.WithField("workspace", ws.Name).Debug("Workspace sem estado do Terraform, pulando")
+			}
 			workspacesWithoutState = append(workspacesWithoutState, ws.Name)
 			continue
 		}
 
-		// Verificar se já existe no S3 (usando nome limpo)
-		cleanName := m.removeEnvironmentSuffix(ws.Name)
-		exists, err := m.s3Client.CheckStateExists(ctx, m.config.TerraformCloud.Organization, cleanName)
-		if err != nil {
-			m.logger.WithError(err).WithField("workspace", ws.Name).Warn("Erro ao verificar existência no S3")
+		if options.SkipLocked && ws.Locked {
+			m.logger.WithField("workspace", ws.Name).Debug("Workspace travado, pulando")
+			stats.SkippedLocked = append(stats.SkippedLocked, ws.Name)
+			continue
+		}
+
+		if options.OnlyLocked && !ws.Locked {
+			m.logger.WithField("workspace", ws.Name).Debug("Workspace não travado, pulando")
+			unlocked = append(unlocked, ws.Name)
+			continue
+		}
+
+		// Com --overwrite o checkpoint é ignorado e o serial decide se o estado é reenviado
+		if !options.Force && !options.Overwrite && m.checkpoint != nil && m.checkpoint.has(ws.Name) {
+			m.logger.WithField("workspace", ws.Name).Debug("Workspace registrado no checkpoint, pulando")
+			checkpointed = append(checkpointed, ws.Name)
+			continue
+		}
+
+		candidates = append(candidates, ws)
+	}
+
+	// Workspaces que colidem na mesma chave do S3 não são migrados para evitar sobrescrita
+	stats.Collisions = m.findKeyCollisions(candidates)
+	colliding := make(map[string]bool)
+	for _, collision := range stats.Collisions {
+		m.logger.WithFields(logrus.Fields{
+			"s3_name":    collision.S3Name,
+			"workspaces": collision.Workspaces,
+		}).Error("Colisão de chave no S3: workspaces seriam gravados no mesmo destino e serão pulados")
+		for _, name := range collision.Workspaces {
+			colliding[name] = true
+		}
+	}
+
+	var toScan []terraform.Workspace
+	for _, ws := range candidates {
+		if !colliding[ws.Name] {
+			toScan = append(toScan, ws)
+		}
+	}
+
+	// Os resultados da verificação no S3 mantêm a ordem da listagem
+	var scanErrors []string
+	for i, result := range m.scanWorkspaces(ctx, toScan, options) {
+		ws := toScan[i]
+
+		if result.err != nil {
 			// Continua mesmo com erro de verificação
+			scanErrors = append(scanErrors, ws.Name)
+		}
+
+		if options.DryRun {
+			stats.Plan = append(stats.Plan, result.plan)
 		}
 
-		if exists {
-			m.logger.WithField("workspace", ws.Name).Debug("Estado já existe no S3, pulando")
+		if result.tooOld {
+			stats.SkippedTooOld = append(stats.SkippedTooOld, ws.Name)
+			continue
+		}
+
+		if result.tooRecent {
+			stats.SkippedRecent = append(stats.SkippedRecent, ws.Name)
+			continue
+		}
+
+		if result.skip {
 			existingStates = append(existingStates, ws.Name)
 			continue
 		}
@@ -228,147 +631,738 @@ func (m *Migrator) getWorkspacesToMigrate(ctx context.Context, projectFilter []s
 		workspacesWithState = append(workspacesWithState, ws)
 	}
 
+	if len(scanErrors) > 0 {
+		m.logger.WithField("workspaces", scanErrors).Warnf("Erro ao verificar existência no S3 de %d workspaces (serão migrados)", len(scanErrors))
+	}
+
+	stats.Skipped = len(workspaces) - len(workspacesWithState)
+
 	// Log de resumo
 	m.logger.WithFields(logrus.Fields{
 		"total_found":      len(workspaces),
 		"with_state":       len(workspacesWithState),
 		"without_state":    len(workspacesWithoutState),
 		"already_migrated": len(existingStates),
+		"checkpointed":     len(checkpointed),
+		"too_old":          len(stats.SkippedTooOld),
+		"too_recent":       len(stats.SkippedRecent),
+		"locked":           len(stats.SkippedLocked),
+		"unlocked":         len(unlocked),
+		"collisions":       len(colliding),
 		"to_migrate":       len(workspacesWithState),
 	}).Info("Análise de workspaces concluída")
 
 	if len(workspacesWithoutState) > 0 {
-		m.logger.WithField("workspaces", workspacesWithoutState).Info("Workspaces sem estado do Terraform (serão ignorados)")
+		m.logger.WithField("workspaces", workspacesWithoutState).Info("Workspaces sem estado do Terraform (serão ignorados, use --include-no-state para registrá-los)")
 	}
 
 	if len(existingStates) > 0 {
 		m.logger.WithField("workspaces", existingStates).Info("Workspaces já migrados anteriormente (serão pulados)")
 	}
 
+	if len(checkpointed) > 0 {
+		m.logger.WithField("workspaces", checkpointed).Info("Workspaces registrados no checkpoint (serão pulados, use --force para reprocessar)")
+	}
+
+	if len(stats.SkippedTooOld) > 0 {
+		m.logger.WithFields(logrus.Fields{
+			"since":      options.Since.Format(time.RFC3339),
+			"workspaces": stats.SkippedTooOld,
+		}).Infof("%d workspaces com estado anterior a --since (serão pulados)", len(stats.SkippedTooOld))
+	}
+
+	if len(stats.SkippedRecent) > 0 {
+		m.logger.WithFields(logrus.Fields{
+			"cutoff":     options.RecentCutoff.Format(time.RFC3339),
+			"workspaces": stats.SkippedRecent,
+		}).Infof("%d workspaces com estado alterado recentemente (serão pulados por --skip-recent)", len(stats.SkippedRecent))
+	}
+
+	if len(stats.SkippedLocked) > 0 {
+		m.logger.WithField("workspaces", stats.SkippedLocked).Warnf("%d workspaces travados (serão pulados por --skip-locked)", len(stats.SkippedLocked))
+	}
+
+	if len(unlocked) > 0 {
+		m.logger.WithField("workspaces", unlocked).Infof("%d workspaces não travados (serão pulados por --only-locked)", len(unlocked))
+	}
+
 	return workspacesWithState, nil
 }
 
-// processBatches processa os workspaces em batches
-func (m *Migrator) processBatches(ctx context.Context, workspaces []terraform.Workspace, options MigrationOptions, stats *MigrationStats) error {
-	batchSize := m.config.Migration.BatchSize
-	totalBatches := (len(workspaces) + batchSize - 1) / batchSize
+// scanResult é o resultado da verificação no S3 de um workspace candidato à migração
+type scanResult struct {
+	skip      bool
+	tooOld    bool // Estado anterior a MigrationOptions.Since
+	tooRecent bool // Estado posterior a MigrationOptions.RecentCutoff
+	plan      PlanEntry
+	err       error
+}
 
-	for i := 0; i < len(workspaces); i += batchSize {
-		end := i + batchSize
-		if end > len(workspaces) {
-			end = len(workspaces)
-		}
+// scanWorkspaces verifica em paralelo, limitado por scan_concurrency, quais workspaces já existem no S3.
+// O resultado de índice i corresponde ao workspace de índice i.
+func (m *Migrator) scanWorkspaces(ctx context.Context, workspaces []terraform.Workspace, options MigrationOptions) []scanResult {
+	results := make([]scanResult, len(workspaces))
 
-		batch := workspaces[i:end]
-		batchNumber := (i / batchSize) + 1
+	sem := make(chan struct{}, m.config.Migration.ScanConcurrency)
+	var wg sync.WaitGroup
 
-		m.logger.WithFields(logrus.Fields{
-			"batch":         batchNumber,
-			"total_batches": totalBatches,
-			"batch_size":    len(batch),
-			"progress":      fmt.Sprintf("%.1f%%", float64(i)/float64(len(workspaces))*100),
-		}).Info("Processando batch")
+	for i, ws := range workspaces {
+		wg.Add(1)
+		go func(result *scanResult, ws terraform.Workspace) {
+			defer wg.Done()
 
-		err := m.processBatch(ctx, batch, options, stats)
-		if err != nil {
-			m.logger.WithError(err).Error("Erro ao processar batch")
-			// Continuar com próximo batch em caso de erro
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			cleanName := m.s3Name(ws.Name)
+
+			// Com --since e --skip-recent, estados fora da janela são descartados antes de consultar o S3
+			if ws.HasState && (!options.Since.IsZero() || !options.RecentCutoff.IsZero()) {
+				createdAt, err := m.stateCreatedAt(ctx, ws)
+				switch {
+				case err != nil && !options.RecentCutoff.IsZero():
+					// Sem a data não é possível garantir que o estado não está em uso: o workspace é pulado
+					m.logger.WithError(err).WithField("workspace", ws.Name).Warn("Erro ao obter a data do estado, workspace será pulado por --skip-recent")
+					result.tooRecent = true
+					result.plan = PlanEntry{
+						WorkspaceName: ws.Name,
+						S3Name:        cleanName,
+						Action:        PlanSkip,
+						Reason:        "data do estado indisponível com --skip-recent",
+					}
+					return
+				case err != nil:
+					m.logger.WithError(err).WithField("workspace", ws.Name).Warn("Erro ao obter a data do estado, o filtro --since não será aplicado")
+				default:
+					if !options.Since.IsZero() && createdAt.Before(options.Since) {
+						result.tooOld = true
+						result.plan = PlanEntry{
+							WorkspaceName: ws.Name,
+							S3Name:        cleanName,
+							Action:        PlanSkip,
+							Reason:        "estado anterior a --since",
+						}
+						return
+					}
+
+					if !options.RecentCutoff.IsZero() && createdAt.After(options.RecentCutoff) {
+						result.tooRecent = true
+						result.plan = PlanEntry{
+							WorkspaceName: ws.Name,
+							S3Name:        cleanName,
+							Action:        PlanSkip,
+							Reason:        "estado alterado dentro da janela de --skip-recent",
+						}
+						return
+					}
+				}
+			}
+
+			// Verificar se já existe no S3 (usando nome limpo)
+			exists, err := m.destination(ws.Name).CheckStateExists(ctx, m.config.TerraformCloud.Organization, cleanName)
+			if err != nil {
+				m.logger.WithError(err).WithField("workspace", ws.Name).Debug("Erro ao verificar existência no S3")
+				result.err = err
+			}
+
+			if options.DryRun {
+				result.plan = m.planWorkspace(ctx, ws, cleanName, exists, options)
+				result.skip = result.plan.Action == PlanSkip
+				return
+			}
+
+			if exists && !(options.Overwrite && m.stateChanged(ctx, ws, cleanName)) {
+				m.logger.WithField("workspace", ws.Name).Debug("Estado já existe no S3, pulando")
+				result.skip = true
+			}
+		}(&results[i], ws)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// stateCreatedAt retorna a data de criação da versão atual do estado
+func (m *Migrator) stateCreatedAt(ctx context.Context, ws terraform.Workspace) (time.Time, error) {
+	version, err := m.currentStateVersion(ctx, ws.ID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return version.CreatedAt, nil
+}
+
+// stateChanged compara o serial atual do Terraform Cloud com o serial registrado no metadata.json do S3.
+// Em caso de erro o estado é considerado alterado, para que seja reenviado.
+func (m *Migrator) stateChanged(ctx context.Context, ws terraform.Workspace, cleanName string) bool {
+	logger := m.logger.WithField("workspace", ws.Name)
+
+	source, target, err := m.compareSerials(ctx, ws, cleanName)
+	if err != nil {
+		logger.WithError(err).Warn("Erro ao comparar seriais, o estado será reenviado")
+		return true
+	}
+
+	if source == target {
+		return false
+	}
+
+	logger.WithFields(logrus.Fields{
+		"source_serial":   source,
+		"migrated_serial": target,
+	}).Info("Estado alterado desde a última migração, será sobrescrito")
+	return true
+}
+
+// compareSerials retorna o serial atual no Terraform Cloud e o serial registrado no metadata.json do S3
+// (-1 se o metadata.json não registrar o serial)
+func (m *Migrator) compareSerials(ctx context.Context, ws terraform.Workspace, cleanName string) (int64, int64, error) {
+	dest := m.destination(ws.Name)
+	reader, ok := dest.(stateReader)
+	if !ok {
+		return 0, 0, unsupportedError(dest, "a leitura do metadata.json migrado")
+	}
+
+	metadata, err := reader.GetStateMetadata(ctx, m.config.TerraformCloud.Organization, cleanName)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	source, err := m.currentSerial(ctx, ws.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Números em JSON são deserializados como float64
+	target := int64(-1)
+	if serial, ok := metadata["serial"].(float64); ok {
+		target = int64(serial)
+	}
+
+	return source, target, nil
+}
+
+// findKeyCollisions agrupa os workspaces cujo nome limpo resulta na mesma chave do S3.
+// Workspaces roteados para buckets diferentes por aws.environment_buckets não colidem.
+func (m *Migrator) findKeyCollisions(workspaces []terraform.Workspace) []KeyCollision {
+	groups := make(map[string][]string)
+	names := make(map[string]string)
+	var order []string
+
+	for _, ws := range workspaces {
+		cleanName := m.s3Name(ws.Name)
+		key := sinkName(m.destination(ws.Name)) + "/" + cleanName
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+			names[key] = cleanName
 		}
+		groups[key] = append(groups[key], ws.Name)
+	}
 
-		// Pequeno delay entre batches para evitar rate limiting
-		if batchNumber < totalBatches {
-			time.Sleep(1 * time.Second)
+	var collisions []KeyCollision
+	for _, key := range order {
+		if len(groups[key]) > 1 {
+			collisions = append(collisions, KeyCollision{
+				S3Name:     names[key],
+				Workspaces: groups[key],
+			})
 		}
 	}
 
-	return nil
+	return collisions
 }
 
-// processBatch processa um batch de workspaces
-func (m *Migrator) processBatch(ctx context.Context, batch []terraform.Workspace, options MigrationOptions, stats *MigrationStats) error {
-	// Usar semáforo para controlar concorrência
-	sem := make(chan struct{}, m.config.Migration.ConcurrentUploads)
-	var wg sync.WaitGroup
+// processWorkspaces distribui os workspaces entre um pool de workers limitado por concurrent_uploads.
+// batch_size define apenas a frequência dos logs de andamento.
+// O cancelamento de ctx interrompe apenas o agendamento: workspaces em andamento são concluídos
+// para não deixar objetos parciais no S3 nem perder o registro no checkpoint. Um prazo em ctx
+// (--timeout) é a exceção: ao esgotar, também cancela os workspaces em andamento.
+func (m *Migrator) processWorkspaces(ctx context.Context, workspaces []terraform.Workspace, options MigrationOptions, stats *MigrationStats) {
+	workCtx := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		workCtx, cancel = context.WithDeadline(workCtx, deadline)
+		defer cancel()
+	}
 	var mu sync.Mutex
 
-	for _, workspace := range batch {
+	stopTicker := m.startProgressTicker(ctx, m.config.Migration.ProgressInterval, len(workspaces), &mu, stats)
+	defer stopTicker()
+
+	stopProgressFile := m.startProgressFile(options.ProgressFile, m.config.Migration.ProgressFileInterval, &mu, stats)
+	defer stopProgressFile()
+
+	// Cancelado ao ultrapassar max_failures ou, com FailFast, em um erro de autenticação,
+	// interrompendo apenas o agendamento
+	ctx, abort := context.WithCancel(ctx)
+	defer abort()
+	queue := make(chan terraform.Workspace)
+	var wg sync.WaitGroup
+
+	workers := m.config.Migration.ConcurrentUploads
+	if workers > len(workspaces) {
+		workers = len(workspaces)
+	}
+
+	for i := 0; i < workers; i++ {
 		wg.Add(1)
-		go func(ws terraform.Workspace) {
+		go func() {
 			defer wg.Done()
+			for ws := range queue {
+				start := time.Now()
+				stateData, err := m.migrateWorkspace(workCtx, ws, options)
+
+				mu.Lock()
+				m.recordResult(ws, stateData, err, time.Since(start), options, stats)
+				if options.MaxFailures > 0 && stats.Failed > options.MaxFailures && !stats.Aborted {
+					stats.Aborted = true
+					stats.AbortReason = fmt.Sprintf("max_failures: %d", options.MaxFailures)
+					m.logger.WithField("max_failures", options.MaxFailures).Errorf("Limite de falhas ultrapassado após %d falhas: nenhum novo workspace será iniciado", stats.Failed)
+					abort()
+				}
+				if options.FailFast && retry.IsAuthError(err) && !stats.Aborted {
+					stats.Aborted = true
+					stats.AbortReason = fmt.Sprintf("--fail-fast: erro de autenticação no workspace %s", ws.Name)
+					m.logger.WithField("workspace", ws.Name).WithError(err).Error("Erro de autenticação ou permissão com --fail-fast: nenhum novo workspace será iniciado")
+					abort()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
 
-			// Adquirir semáforo
-			sem <- struct{}{}
-			defer func() { <-sem }()
+schedule:
+	for _, ws := range workspaces {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case queue <- ws:
+		case <-ctx.Done():
+			break schedule
+		}
+	}
+	close(queue)
+
+	// Os workers ainda podem estar gravando stats.Aborted
+	mu.Lock()
+	interrupted := ctx.Err() != nil && !stats.Aborted
+	mu.Unlock()
+	if interrupted {
+		m.logger.Warn("Interrupção recebida: nenhum novo workspace será iniciado, aguardando os que estão em andamento")
+	}
 
-			err := m.migrateWorkspace(ctx, ws, options.DryRun)
+	wg.Wait()
+}
 
-			mu.Lock()
-			if err != nil {
-				stats.Failed++
-				stats.FailedItems = append(stats.FailedItems, FailedMigration{
-					WorkspaceName: ws.Name,
-					Error:         err.Error(),
-				})
-				m.logger.WithError(err).WithField("workspace", ws.Name).Error("Falha na migração do workspace")
-			} else {
-				stats.Successful++
-				m.logger.WithField("workspace", ws.Name).Info("Workspace migrado com sucesso")
+// recordResult registra o resultado de um workspace nas estatísticas e no checkpoint.
+// O chamador deve proteger o acesso com um mutex.
+func (m *Migrator) recordResult(ws terraform.Workspace, stateData *terraform.StateData, err error, duration time.Duration, options MigrationOptions, stats *MigrationStats) {
+	result := WorkspaceResult{
+		WorkspaceName: ws.Name,
+		S3Name:        m.s3Name(ws.Name),
+		Success:       err == nil,
+		Duration:      duration,
+	}
+	if stateData != nil {
+		result.Bytes = stateData.Size
+		result.Serial = stateData.Version
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	stats.WorkspaceResults = append(stats.WorkspaceResults, result)
+	if err != nil {
+		stats.Failed++
+		stats.FailedItems = append(stats.FailedItems, FailedMigration{
+			WorkspaceName: ws.Name,
+			Category:      failureCategory(err),
+			Error:         err.Error(),
+			Err:           fmt.Errorf("workspace %s: %w", ws.Name, err),
+		})
+		m.logger.WithError(err).WithFields(logrus.Fields{
+			"workspace": ws.Name,
+			"category":  failureCategory(err),
+		}).Error("Falha na migração do workspace")
+	} else {
+		stats.Successful++
+		m.logger.WithField("workspace", ws.Name).Info("Workspace migrado com sucesso")
+
+		// Workspaces sem estado não entram no checkpoint nem no manifesto, para que o estado
+		// seja migrado quando existir
+		if ws.HasState && !options.DryRun && m.checkpoint != nil {
+			if err := m.checkpoint.record(ws.Name, stateData.Version); err != nil {
+				m.logger.WithError(err).WithField("workspace", ws.Name).Warn("Erro ao atualizar checkpoint")
 			}
-			mu.Unlock()
-		}(workspace)
+		}
+
+		if ws.HasState && !options.DryRun && m.manifest != nil {
+			destination := m.destination(ws.Name)
+			m.manifest.record(ManifestEntry{
+				Workspace:  ws.Name,
+				S3Name:     result.S3Name,
+				Bucket:     sinkName(destination),
+				StateKey:   sinkStateKey(destination, m.config.TerraformCloud.Organization, result.S3Name),
+				Serial:     stateData.Version,
+				MigratedAt: time.Now().UTC(),
+			})
+		}
+	}
+	if m.audit != nil {
+		m.writeAudit(ws, result)
 	}
 
-	wg.Wait()
-	return nil
+	m.progress.update(stats.Successful, stats.Failed)
+
+	done := stats.Successful + stats.Failed
+	if done%m.config.Migration.BatchSize == 0 || done == stats.Total {
+		m.logger.WithFields(logrus.Fields{
+			"processed":  done,
+			"total":      stats.Total,
+			"successful": stats.Successful,
+			"failed":     stats.Failed,
+			"progress":   fmt.Sprintf("%.1f%%", float64(done)/float64(stats.Total)*100),
+		}).Info("Andamento da migração")
+	}
+}
+
+// writeAudit acrescenta o resultado do workspace ao audit log. Falhas de escrita são apenas registradas.
+func (m *Migrator) writeAudit(ws terraform.Workspace, result WorkspaceResult) {
+	entry := auditEntry{
+		Timestamp: time.Now().UTC(),
+		Workspace: ws.Name,
+		S3Name:    result.S3Name,
+		Serial:    result.Serial,
+		Bytes:     result.Bytes,
+		Outcome:   auditOutcomeSuccess,
+		Error:     result.Error,
+	}
+	if ws.HasState {
+		entry.Key = sinkStateKey(m.destination(ws.Name), m.config.TerraformCloud.Organization, result.S3Name)
+	}
+	if !result.Success {
+		entry.Outcome = auditOutcomeFailed
+	}
+
+	if err := m.audit.write(entry); err != nil {
+		m.logger.WithError(err).WithField("workspace", ws.Name).Warn("Erro ao gravar audit log")
+	}
 }
 
 // migrateWorkspace migra um workspace específico
-func (m *Migrator) migrateWorkspace(ctx context.Context, workspace terraform.Workspace, dryRun bool) error {
+func (m *Migrator) migrateWorkspace(ctx context.Context, workspace terraform.Workspace, options MigrationOptions) (*terraform.StateData, error) {
 	logger := m.logger.WithField("workspace", workspace.Name)
 
-	// Obter estado do Terraform Cloud
-	stateData, err := m.tfClient.GetWorkspaceState(ctx, workspace.ID)
+	// Obter nome limpo para upload no S3
+	stateName := m.s3Name(workspace.Name)
+
+	if !workspace.HasState {
+		return m.recordNoState(ctx, workspace, stateName, options)
+	}
+
+	// O estado é transferido em streaming, então download e upload são retentados juntos
+	var stateData *terraform.StateData
+	var digest string
+	err := retry.Do(ctx, m.backoff(), func() error {
+		attemptCtx, cancel := m.withOperationTimeout(ctx)
+		defer cancel()
+
+		var err error
+		stateData, digest, err = m.transferState(attemptCtx, workspace, stateName, options)
+		return err
+	}, func(attempt int, delay time.Duration, err error) {
+		logger.WithError(err).WithField("attempt", attempt).Warnf("Falha na transferência do estado, tentando novamente em %v", delay)
+	})
 	if err != nil {
-		return fmt.Errorf("erro ao obter estado: %w", err)
+		return nil, err
 	}
 
-	if dryRun {
-		logger.WithField("state_size", len(stateData.StateContent)).Info("Dry run: estado seria migrado")
+	if options.IncludeHistory {
+		if err := m.migrateHistory(ctx, workspace, stateName, options); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.IncludeVariables {
+		if err := m.migrateVariables(ctx, workspace, stateName, options); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.DryRun {
+		logger.WithField("state_size", stateData.Size).Info("Dry run: estado seria migrado")
+		return stateData, nil
+	}
+
+	if options.CreateLockEntries {
+		err = retry.Do(ctx, m.backoff(), func() error {
+			dest := m.destination(workspace.Name)
+			writer, ok := dest.(lockWriter)
+			if !ok {
+				return unsupportedError(dest, "--create-lock-entries")
+			}
+			return writer.CreateLockEntry(ctx, m.config.TerraformCloud.Organization, stateName, digest)
+		}, func(attempt int, delay time.Duration, err error) {
+			logger.WithError(err).WithField("attempt", attempt).Warnf("Falha ao gravar digest no DynamoDB, tentando novamente em %v", delay)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar entrada de lock: %w", err)
+		}
+	}
+
+	return stateData, nil
+}
+
+// recordNoState grava apenas o metadata.json de um workspace sem estado (--include-no-state),
+// para que o inventário no S3 registre todos os workspaces
+func (m *Migrator) recordNoState(ctx context.Context, workspace terraform.Workspace, stateName string, options MigrationOptions) (*terraform.StateData, error) {
+	logger := m.logger.WithField("workspace", workspace.Name)
+
+	metadata := map[string]interface{}{
+		"workspace_id":   workspace.ID,
+		"workspace_name": workspace.Name,
+		"organization":   m.config.TerraformCloud.Organization,
+		"source":         terraform.StateSource,
+		"has_state":      false,
+	}
+	stateData := &terraform.StateData{WorkspaceName: workspace.Name, Metadata: metadata}
+
+	if options.DryRun {
+		logger.Info("Dry run: workspace sem estado seria registrado apenas com metadata.json")
+		return stateData, nil
+	}
+
+	err := retry.Do(ctx, m.backoff(), func() error {
+		writer, err := m.artifactWriter(workspace.Name, "--include-no-state")
+		if err != nil {
+			return err
+		}
+		return writer.UploadMetadata(ctx, m.config.TerraformCloud.Organization, stateName, metadata)
+	}, func(attempt int, delay time.Duration, err error) {
+		logger.WithError(err).WithField("attempt", attempt).Warnf("Falha ao gravar metadados, tentando novamente em %v", delay)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao registrar workspace sem estado: %w", err)
+	}
+
+	return stateData, nil
+}
+
+// transferState abre o estado no Terraform Cloud e o envia em streaming ao S3,
+// retornando o digest MD5 do conteúdo enviado. Em dry run o stream é apenas aberto e fechado.
+func (m *Migrator) transferState(ctx context.Context, workspace terraform.Workspace, stateName string, options MigrationOptions) (*terraform.StateData, string, error) {
+	stateData, body, err := m.openWorkspaceState(ctx, workspace.ID)
+	if err != nil {
+		return nil, "", withCategory(CategoryDownload, fmt.Errorf("erro ao obter estado: %w", err))
+	}
+	defer body.Close()
+
+	if options.DryRun {
+		return stateData, "", nil
+	}
+
+	logger := m.logger.WithField("workspace", workspace.Name)
+
+	// O bloco backend é removido durante o streaming, sem alterar os demais bytes. O metadata.json
+	// registra a remoção para que o verify compare o estado com o mesmo bloco removido.
+	var source io.ReadCloser = downloadReader{body}
+	if options.RewriteBackend {
+		stripper := newBackendStripper(source, func() {
+			stateData.Metadata["backend_rewritten"] = true
+			logger.Info("Bloco backend do Terraform Cloud removido do estado (--rewrite-backend)")
+		})
+		source = struct {
+			io.Reader
+			io.Closer
+		}{stripper, source}
+	}
+
+	// O lineage e as contagens de recursos e outputs são registrados no metadata.json,
+	// gravado pelo UploadState após o upload do estado
+	content := m.validated(source, options, int64(stateData.Version), func(identity stateIdentity) {
+		identity.record(stateData.Metadata)
+		if remoteBackendTypes[identity.Backend] {
+			logger.WithField("backend", identity.Backend).Warn("Estado contém um bloco backend apontando para o Terraform Cloud, o que pode confundir o terraform init; use --rewrite-backend para removê-lo")
+		}
+	})
+	defer content.Close()
+
+	hash := md5.New()
+	counter := &countingReader{reader: io.TeeReader(content, hash)}
+
+	err = m.destination(workspace.Name).UploadState(ctx, m.config.TerraformCloud.Organization, stateName, counter, stateData.Metadata)
+	if err != nil {
+		return nil, "", withCategory(CategoryUpload, fmt.Errorf("erro ao fazer upload: %w", err))
+	}
+
+	stateData.Size = counter.count
+	return stateData, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// migrateHistory envia todas as versões de estado do workspace para a pasta de histórico no S3
+func (m *Migrator) migrateHistory(ctx context.Context, workspace terraform.Workspace, stateName string, options MigrationOptions) error {
+	logger := m.logger.WithField("workspace", workspace.Name)
+	onRetry := func(attempt int, delay time.Duration, err error) {
+		logger.WithError(err).WithField("attempt", attempt).Warnf("Falha na migração do histórico, tentando novamente em %v", delay)
+	}
+
+	history, ok := m.tfClient.(historySource)
+	if !ok {
+		return m.unsupportedSourceError("--include-history")
+	}
+
+	var versions []terraform.StateVersion
+	err := retry.Do(ctx, m.backoff(), func() error {
+		var err error
+		versions, err = history.ListStateVersions(ctx, workspace.Name)
+		return err
+	}, onRetry)
+	if err != nil {
+		return fmt.Errorf("erro ao listar histórico de estados: %w", err)
+	}
+
+	if options.DryRun {
+		logger.WithField("versions", len(versions)).Info("Dry run: histórico de estados seria migrado")
 		return nil
 	}
 
-	// Obter nome limpo para upload no S3
-	stateName := m.removeEnvironmentSuffix(workspace.Name)
-
-	// Retry logic para upload
-	var uploadErr error
-	for attempt := 1; attempt <= m.config.Migration.RetryAttempts; attempt++ {
-		uploadErr = m.s3Client.UploadState(
-			ctx,
-			m.config.TerraformCloud.Organization,
-			stateName,
-			stateData.StateContent,
-			stateData.Metadata,
-		)
-
-		if uploadErr == nil {
-			break
+	for _, version := range versions {
+		err := retry.Do(ctx, m.backoff(), func() error {
+			attemptCtx, cancel := m.withOperationTimeout(ctx)
+			defer cancel()
+
+			body, err := history.OpenStateVersion(attemptCtx, workspace.Name, version)
+			if err != nil {
+				return withCategory(CategoryDownload, err)
+			}
+			defer body.Close()
+
+			content := m.validated(downloadReader{body}, options, version.Serial, nil)
+			defer content.Close()
+
+			writer, err := m.artifactWriter(workspace.Name, "--include-history")
+			if err != nil {
+				return err
+			}
+			err = writer.UploadStateVersion(attemptCtx, m.config.TerraformCloud.Organization, stateName, version.Serial, content)
+			return withCategory(CategoryUpload, err)
+		}, onRetry)
+		if err != nil {
+			return fmt.Errorf("erro ao migrar versão %d do histórico: %w", version.Serial, err)
+		}
+	}
+
+	logger.WithField("versions", len(versions)).Info("Histórico de estados migrado")
+	return nil
+}
+
+// migrateVariables grava as variáveis do workspace em variables.json, ao lado do estado
+func (m *Migrator) migrateVariables(ctx context.Context, workspace terraform.Workspace, stateName string, options MigrationOptions) error {
+	logger := m.logger.WithField("workspace", workspace.Name)
+
+	source, ok := m.tfClient.(variableSource)
+	if !ok {
+		return m.unsupportedSourceError("--include-variables")
+	}
+
+	var variables []terraform.Variable
+	err := retry.Do(ctx, m.backoff(), func() error {
+		var err error
+		variables, err = source.GetWorkspaceVariables(ctx, workspace.ID)
+		if err != nil || options.DryRun {
+			return err
+		}
+
+		content, err := json.MarshalIndent(map[string]interface{}{
+			"workspace":    workspace.Name,
+			"workspace_id": workspace.ID,
+			"variables":    variables,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		writer, err := m.artifactWriter(workspace.Name, "--include-variables")
+		if err != nil {
+			return err
 		}
+		return writer.UploadVariables(ctx, m.config.TerraformCloud.Organization, stateName, content)
+	}, func(attempt int, delay time.Duration, err error) {
+		logger.WithError(err).WithField("attempt", attempt).Warnf("Falha na migração das variáveis, tentando novamente em %v", delay)
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao migrar variáveis: %w", err)
+	}
 
-		if attempt < m.config.Migration.RetryAttempts {
-			delay := time.Duration(attempt) * time.Second
-			logger.WithError(uploadErr).WithField("attempt", attempt).Warnf("Falha no upload, tentando novamente em %v", delay)
-			time.Sleep(delay)
+	sensitive := 0
+	for _, variable := range variables {
+		if variable.Sensitive {
+			sensitive++
 		}
 	}
 
-	if uploadErr != nil {
-		return fmt.Errorf("erro ao fazer upload após %d tentativas: %w", m.config.Migration.RetryAttempts, uploadErr)
+	fields := logrus.Fields{
+		"variables": len(variables),
+		"sensitive": sensitive,
+	}
+	if options.DryRun {
+		logger.WithFields(fields).Info("Dry run: variáveis seriam migradas")
+	} else {
+		logger.WithFields(fields).Info("Variáveis migradas (valores sensíveis omitidos)")
 	}
 
 	return nil
 }
 
+// validated envolve o stream com a validação do formato de estado do Terraform e do serial esperado.
+// Com SkipValidation o conteúdo não é rejeitado, mas recursos e outputs continuam sendo contados.
+func (m *Migrator) validated(body io.ReadCloser, options MigrationOptions, expectedSerial int64, onValid func(stateIdentity)) io.ReadCloser {
+	if !options.SkipValidation {
+		return newStateValidator(body, expectedSerial, onValid)
+	}
+	if onValid == nil {
+		return body
+	}
+	return newStateCounter(body, onValid)
+}
+
+// withOperationTimeout limita a duração de uma tentativa de transferência, para que downloads
+// ou uploads travados expirem e sejam retentados em vez de bloquear o worker indefinidamente
+func (m *Migrator) withOperationTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if m.config.Migration.OperationTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, m.config.Migration.OperationTimeout)
+}
+
+// backoff retorna a política de retentativas configurada
+func (m *Migrator) backoff() retry.Backoff {
+	return retry.Backoff{
+		Attempts:  m.config.Migration.RetryAttempts,
+		BaseDelay: m.config.Migration.RetryBaseDelay,
+		MaxDelay:  m.config.Migration.RetryMaxDelay,
+	}
+}
+
+// slowestCount é a quantidade de workspaces mais lentos exibida ao final da migração
+const slowestCount = 5
+
+// slowestResults retorna os n resultados com maior duração, do mais lento para o mais rápido
+func slowestResults(results []WorkspaceResult, n int) []WorkspaceResult {
+	sorted := make([]WorkspaceResult, len(results))
+	copy(sorted, results)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Duration > sorted[j].Duration
+	})
+
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
 // logFinalStats registra as estatísticas finais da migração
 func (m *Migrator) logFinalStats(stats *MigrationStats, dryRun bool) {
 	mode := "Migração"
@@ -376,6 +1370,12 @@ func (m *Migrator) logFinalStats(stats *MigrationStats, dryRun bool) {
 		mode = "Dry run"
 	}
 
+	if stats.Aborted {
+		mode += " abortada"
+	} else if stats.Interrupted {
+		mode += " interrompida"
+	}
+
 	m.logger.WithFields(logrus.Fields{
 		"mode":       mode,
 		"total":      stats.Total,
@@ -384,6 +1384,10 @@ func (m *Migrator) logFinalStats(stats *MigrationStats, dryRun bool) {
 		"duration":   stats.Duration.String(),
 	}).Info("Migração finalizada")
 
+	if dryRun {
+		m.logPlan(stats.Plan)
+	}
+
 	if len(stats.FailedItems) > 0 {
 		m.logger.Error("Workspaces que falharam:")
 		for _, failed := range stats.FailedItems {
@@ -394,6 +1398,41 @@ func (m *Migrator) logFinalStats(stats *MigrationStats, dryRun bool) {
 		}
 	}
 
+	if len(stats.Collisions) > 0 {
+		m.logger.Warn("Workspaces pulados por colisão de chave no S3:")
+		for _, collision := range stats.Collisions {
+			m.logger.WithFields(logrus.Fields{
+				"s3_name":    collision.S3Name,
+				"workspaces": strings.Join(collision.Workspaces, ", "),
+			}).Warn("Colisão de chave")
+		}
+	}
+
+	if len(stats.SkippedNoState) > 0 {
+		m.logger.WithField("workspaces", stats.SkippedNoState).Warn("Workspaces solicitados sem estado do Terraform (não migrados)")
+	}
+
+	if len(stats.SkippedTooOld) > 0 {
+		m.logger.WithField("count", len(stats.SkippedTooOld)).Info("Workspaces pulados por estado anterior a --since")
+	}
+
+	if len(stats.SkippedRecent) > 0 {
+		m.logger.WithField("count", len(stats.SkippedRecent)).Info("Workspaces pulados por estado alterado recentemente (--skip-recent)")
+	}
+
+	if len(stats.SkippedLocked) > 0 {
+		m.logger.WithField("count", len(stats.SkippedLocked)).Info("Workspaces pulados por estarem travados (--skip-locked)")
+	}
+
+	for _, result := range slowestResults(stats.WorkspaceResults, slowestCount) {
+		m.logger.WithFields(logrus.Fields{
+			"workspace": result.WorkspaceName,
+			"duration":  result.Duration.Round(time.Millisecond).String(),
+			"bytes":     result.Bytes,
+			"success":   result.Success,
+		}).Info("Workspace mais lento")
+	}
+
 	// Calcular taxa de sucesso
 	if stats.Total > 0 {
 		successRate := float64(stats.Successful) / float64(stats.Total) * 100
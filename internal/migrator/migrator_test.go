@@ -0,0 +1,306 @@
+package migrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-cloud-s3-migrator/internal/terraform"
+
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestGetWorkspacesToMigrate(t *testing.T) {
+	workspaces := []terraform.Workspace{
+		{ID: "ws-1", Name: "network", HasState: true},
+		{ID: "ws-2", Name: "empty"},
+		{ID: "ws-3", Name: "app-prd", HasState: true, Locked: true},
+		{ID: "ws-4", Name: "app-stg", HasState: true},
+		{ID: "ws-5", Name: "billing", HasState: true},
+	}
+
+	tests := []struct {
+		name          string
+		options       MigrationOptions
+		existing      []string
+		want          []string
+		wantNoState   []string
+		wantLocked    []string
+		wantRecent    []string
+		wantCollision []string
+		wantSkipped   int
+	}{
+		{
+			name:          "todos os workspaces",
+			want:          []string{"network", "billing"},
+			wantCollision: []string{"app-prd", "app-stg"},
+			wantSkipped:   3,
+		},
+		{
+			name:          "estado já existente no destino",
+			existing:      []string{"billing"},
+			want:          []string{"network"},
+			wantCollision: []string{"app-prd", "app-stg"},
+			wantSkipped:   4,
+		},
+		{
+			name:        "nomes exatos com workspace sem estado",
+			options:     MigrationOptions{Projects: []string{"empty", "network", "missing"}},
+			want:        []string{"network"},
+			wantNoState: []string{"empty"},
+			wantSkipped: 1,
+		},
+		{
+			name:        "glob com exclusão",
+			options:     MigrationOptions{Projects: []string{"app-*", "billing"}, Exclude: []string{"app-stg"}},
+			want:        []string{"app-prd", "billing"},
+			wantSkipped: 0,
+		},
+		{
+			name:        "pulando travados",
+			options:     MigrationOptions{Projects: []string{"app-*"}, SkipLocked: true},
+			want:        []string{"app-stg"},
+			wantLocked:  []string{"app-prd"},
+			wantSkipped: 1,
+		},
+		{
+			// A origem em memória não informa a data do estado: o workspace não pode ser liberado
+			name:        "data indisponível com --skip-recent",
+			options:     MigrationOptions{Projects: []string{"network", "billing"}, RecentCutoff: time.Now().Add(-time.Hour)},
+			wantRecent:  []string{"network", "billing"},
+			wantSkipped: 2,
+		},
+		{
+			name:        "data indisponível com --since",
+			options:     MigrationOptions{Projects: []string{"network", "billing"}, Since: time.Now().Add(-time.Hour)},
+			want:        []string{"network", "billing"},
+			wantSkipped: 0,
+		},
+		{
+			name:        "apenas travados",
+			options:     MigrationOptions{Projects: []string{"app-*"}, OnlyLocked: true},
+			want:        []string{"app-prd"},
+			wantSkipped: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &fakeSink{states: make(map[string][]byte)}
+			for _, name := range tt.existing {
+				sink.states[name] = []byte("{}")
+			}
+			m := newTestMigrator(t, &fakeSource{workspaces: workspaces}, sink)
+
+			stats := &MigrationStats{}
+			got, err := m.getWorkspacesToMigrate(context.Background(), tt.options, stats)
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+
+			if names := workspaceNames(got); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("workspaces = %v, esperado %v", names, tt.want)
+			}
+			if !reflect.DeepEqual(stats.SkippedNoState, tt.wantNoState) {
+				t.Errorf("SkippedNoState = %v, esperado %v", stats.SkippedNoState, tt.wantNoState)
+			}
+			if !reflect.DeepEqual(stats.SkippedRecent, tt.wantRecent) {
+				t.Errorf("SkippedRecent = %v, esperado %v", stats.SkippedRecent, tt.wantRecent)
+			}
+			if !reflect.DeepEqual(stats.SkippedLocked, tt.wantLocked) {
+				t.Errorf("SkippedLocked = %v, esperado %v", stats.SkippedLocked, tt.wantLocked)
+			}
+
+			var collisions []string
+			for _, collision := range stats.Collisions {
+				collisions = append(collisions, collision.Workspaces...)
+			}
+			if !reflect.DeepEqual(collisions, tt.wantCollision) {
+				t.Errorf("colisões = %v, esperado %v", collisions, tt.wantCollision)
+			}
+			if stats.Skipped != tt.wantSkipped {
+				t.Errorf("Skipped = %d, esperado %d", stats.Skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+// workspaceNames retorna os nomes dos workspaces, na ordem recebida
+func workspaceNames(workspaces []terraform.Workspace) []string {
+	var names []string
+	for _, ws := range workspaces {
+		names = append(names, ws.Name)
+	}
+	return names
+}
+
+func TestMigrateWorkspacePreservesState(t *testing.T) {
+	// Formatação, ordem das chaves, escapes e números grandes mudariam se o estado fosse reserializado
+	state := "{\n\t\"version\": 4,\n\t\"terraform_version\": \"1.5.7\",\n\t\"serial\": 42,\n" +
+		"\t\"lineage\": \"5f0c6a1e-7d2b-4b8e-9a63-1f2d3c4b5a69\",\n" +
+		"\t\"outputs\": {\"url\": {\"value\": \"https://example.com/?a=1\\u0026b=2\", \"type\": \"string\"}},\n" +
+		"\t\"resources\": [{\"mode\": \"managed\", \"type\": \"aws_s3_bucket\", \"name\": \"logs\", " +
+		"\"instances\": [{\"attributes\": {\"id\": \"logs\", \"size\": 12345678901234567890}}]}],\n" +
+		"\t\"check_results\": null\n}\n"
+
+	workspace := terraform.Workspace{ID: "ws-1", Name: "network", HasState: true}
+	source := &fakeSource{
+		workspaces: []terraform.Workspace{workspace},
+		states:     map[string][]byte{"ws-1": []byte(state)},
+	}
+	sink := &fakeSink{}
+	m := newTestMigrator(t, source, sink)
+
+	stateData, err := m.migrateWorkspace(context.Background(), workspace, MigrationOptions{})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	uploaded := string(sink.states["network"])
+	if uploaded != state {
+		t.Errorf("conteúdo enviado difere do original:\n%s\nesperado:\n%s", uploaded, state)
+	}
+	if stateData.Size != int64(len(state)) {
+		t.Errorf("Size = %d, esperado %d", stateData.Size, len(state))
+	}
+	if stateData.Version != 42 {
+		t.Errorf("serial = %d, esperado 42", stateData.Version)
+	}
+	if lineage := stateData.Metadata["lineage"]; lineage != "5f0c6a1e-7d2b-4b8e-9a63-1f2d3c4b5a69" {
+		t.Errorf("lineage = %v", lineage)
+	}
+	if resources := stateData.Metadata["resource_count"]; resources != 1 {
+		t.Errorf("resource_count = %v, esperado 1", resources)
+	}
+	if outputs := stateData.Metadata["output_count"]; outputs != 1 {
+		t.Errorf("output_count = %v, esperado 1", outputs)
+	}
+}
+
+func TestMigrateWorkspaceRejectsSerialMismatch(t *testing.T) {
+	workspace := terraform.Workspace{ID: "ws-1", Name: "network", HasState: true}
+	source := &fakeSource{
+		workspaces: []terraform.Workspace{workspace},
+		states:     map[string][]byte{"ws-1": []byte(`{"version":4,"terraform_version":"1.5.7","serial":7}`)},
+	}
+	sink := &fakeSink{}
+	m := newTestMigrator(t, source, sink)
+
+	// Versão informada pelo Terraform Cloud diferente do serial do conteúdo
+	m.tfClient = serialOverride{source, 8}
+
+	if _, err := m.migrateWorkspace(context.Background(), workspace, MigrationOptions{}); err == nil {
+		t.Fatal("esperado erro de validação do serial")
+	}
+	if _, ok := sink.states["network"]; ok {
+		t.Error("estado com serial divergente não deveria ser gravado")
+	}
+}
+
+// serialOverride altera a versão informada pela origem, simulando um conteúdo divergente
+type serialOverride struct {
+	*fakeSource
+	version int
+}
+
+func (s serialOverride) GetWorkspaceState(ctx context.Context, workspaceID string) (*terraform.StateData, error) {
+	stateData, err := s.fakeSource.GetWorkspaceState(ctx, workspaceID)
+	if err == nil {
+		stateData.Version = s.version
+	}
+	return stateData, err
+}
+
+func TestMigrateReportsCollisionsWithNothingToMigrate(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	source := &fakeSource{workspaces: []terraform.Workspace{
+		{ID: "ws-1", Name: "app-prd", HasState: true},
+		{ID: "ws-2", Name: "app-stg", HasState: true},
+	}}
+	m := newTestMigrator(t, source, &fakeSink{})
+
+	stats, err := m.Migrate(context.Background(), MigrationOptions{})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if stats.Total != 0 || len(stats.Collisions) != 1 {
+		t.Fatalf("Total = %d, colisões = %v", stats.Total, stats.Collisions)
+	}
+
+	// O resumo final é registrado mesmo sem workspaces a migrar
+	reported := false
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Colisão de chave" && entry.Data["s3_name"] == "app" {
+			reported = true
+		}
+	}
+	if !reported {
+		t.Error("colisão não registrada no resumo final")
+	}
+}
+
+func TestMigrateRejectsLockEntriesWithCompression(t *testing.T) {
+	m := newTestMigrator(t, &fakeSource{}, &fakeSink{})
+	m.config.AWS.DynamoDBTable = "terraform-locks"
+	m.config.Migration.Compress = true
+
+	if _, err := m.Migrate(context.Background(), MigrationOptions{CreateLockEntries: true}); err == nil {
+		t.Fatal("esperado erro ao combinar --create-lock-entries com migration.compress")
+	}
+}
+
+func TestMigrateAbortsAfterMaxFailures(t *testing.T) {
+	source := &fakeSource{states: make(map[string][]byte)}
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("ws-%d", i)
+		source.workspaces = append(source.workspaces, terraform.Workspace{ID: id, Name: fmt.Sprintf("app-%d", i), HasState: true})
+		source.states[id] = []byte(validState)
+	}
+	sink := &fakeSink{uploadErr: errors.New("acesso negado")}
+	m := newTestMigrator(t, source, sink)
+	m.config.Migration.ConcurrentUploads = 1
+
+	stats, err := m.Migrate(context.Background(), MigrationOptions{MaxFailures: 1})
+	if err == nil || !strings.Contains(err.Error(), "migração abortada") {
+		t.Fatalf("erro = %v, esperado abort por max_failures", err)
+	}
+	if !stats.Aborted || !strings.Contains(stats.AbortReason, "max_failures") {
+		t.Fatalf("Aborted = %v, AbortReason = %q", stats.Aborted, stats.AbortReason)
+	}
+
+	// O limite é ultrapassado na segunda falha; no máximo um workspace já entregue ao worker
+	// ainda é processado
+	if processed := stats.Successful + stats.Failed; processed < 2 || processed > 3 {
+		t.Fatalf("%d workspaces processados, esperado o agendamento interrompido após 2", processed)
+	}
+}
+
+func TestMigrateWritesProgressFileWithNothingToMigrate(t *testing.T) {
+	m := newTestMigrator(t, &fakeSource{}, &fakeSink{})
+	path := filepath.Join(t.TempDir(), "progress.json")
+
+	if _, err := m.Migrate(context.Background(), MigrationOptions{ProgressFile: path}); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("arquivo de progresso não gravado: %v", err)
+	}
+	var snapshot progressSnapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Running {
+		t.Error("snapshot final com running: true")
+	}
+}
@@ -0,0 +1,61 @@
+package migrator
+
+import (
+	"regexp"
+	"strings"
+
+	"terraform-cloud-s3-migrator/internal/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// invalidNameChars casa caracteres que não devem aparecer no nome do workspace na chave do S3
+	invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+	// repeatedDashes casa sequências de hífens geradas pela substituição de caracteres
+	repeatedDashes = regexp.MustCompile(`-{2,}`)
+)
+
+// s3Name retorna o nome usado nas chaves do S3 para o workspace: remove o sufixo de ambiente
+// e aplica as regras de migration.name_rules. Toda verificação, upload e comparação no S3 deve usá-lo.
+func (m *Migrator) s3Name(workspaceName string) string {
+	cleanName := m.removeEnvironmentSuffix(workspaceName)
+
+	normalized := normalizeName(cleanName, m.config.Migration.NameRules)
+	if normalized == cleanName {
+		return cleanName
+	}
+
+	// Registrar o mapeamento apenas na primeira vez em que o workspace é normalizado
+	if _, logged := m.normalizedNames.LoadOrStore(workspaceName, normalized); !logged {
+		m.logger.WithFields(logrus.Fields{
+			"original_name":   workspaceName,
+			"normalized_name": normalized,
+		}).Info("Nome do workspace normalizado para o S3")
+	}
+
+	return normalized
+}
+
+// normalizeName aplica as regras de normalização na ordem lowercase, sanitize e collapse,
+// independentemente da ordem em que foram configuradas
+func normalizeName(name string, rules []string) string {
+	enabled := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		enabled[rule] = true
+	}
+
+	if enabled[config.NameRuleLowercase] {
+		name = strings.ToLower(name)
+	}
+
+	if enabled[config.NameRuleSanitize] {
+		name = invalidNameChars.ReplaceAllString(name, "-")
+	}
+
+	if enabled[config.NameRuleCollapse] {
+		name = strings.Trim(repeatedDashes.ReplaceAllString(name, "-"), "-")
+	}
+
+	return name
+}
@@ -0,0 +1,94 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-cloud-s3-migrator/internal/terraform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PlanAction representa o que a migração faria no S3 para um workspace
+type PlanAction string
+
+const (
+	PlanCreate    PlanAction = "CREATE"
+	PlanOverwrite PlanAction = "OVERWRITE"
+	PlanSkip      PlanAction = "SKIP"
+)
+
+// PlanEntry descreve a ação prevista em dry run para um workspace
+type PlanEntry struct {
+	WorkspaceName string     `json:"workspace"`
+	S3Name        string     `json:"s3_name"`
+	Action        PlanAction `json:"action"`
+	SourceSerial  int64      `json:"source_serial,omitempty"`
+	TargetSerial  int64      `json:"target_serial,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+}
+
+// planWorkspace classifica o workspace comparando o serial do Terraform Cloud com o metadata.json do S3
+func (m *Migrator) planWorkspace(ctx context.Context, ws terraform.Workspace, cleanName string, exists bool, options MigrationOptions) PlanEntry {
+	entry := PlanEntry{
+		WorkspaceName: ws.Name,
+		S3Name:        cleanName,
+		Action:        PlanCreate,
+	}
+
+	if !ws.HasState {
+		entry.Reason = "workspace sem estado: apenas metadata.json"
+	}
+
+	if exists {
+		source, target, err := m.compareSerials(ctx, ws, cleanName)
+		switch {
+		case err != nil:
+			entry.Reason = fmt.Sprintf("erro ao comparar seriais: %v", err)
+		case source == target:
+			entry.SourceSerial, entry.TargetSerial = source, target
+			entry.Reason = "serial idêntico no S3"
+		default:
+			entry.SourceSerial, entry.TargetSerial = source, target
+			entry.Reason = "serial diferente no S3"
+		}
+
+		// Assim como na migração real, o estado só é reenviado com --overwrite e serial diferente (ou desconhecido)
+		switch {
+		case !options.Overwrite:
+			entry.Action = PlanSkip
+			if err == nil && source != target {
+				entry.Reason += " (use --overwrite para atualizar)"
+			}
+		case err == nil && source == target:
+			entry.Action = PlanSkip
+		default:
+			entry.Action = PlanOverwrite
+		}
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"workspace":     entry.WorkspaceName,
+		"s3_name":       entry.S3Name,
+		"action":        entry.Action,
+		"source_serial": entry.SourceSerial,
+		"target_serial": entry.TargetSerial,
+		"reason":        entry.Reason,
+	}).Info("Dry run: plano de migração")
+
+	return entry
+}
+
+// logPlan registra a contagem de ações do plano de dry run
+func (m *Migrator) logPlan(plan []PlanEntry) {
+	counts := make(map[PlanAction]int)
+	for _, entry := range plan {
+		counts[entry.Action]++
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"create":    counts[PlanCreate],
+		"overwrite": counts[PlanOverwrite],
+		"skip":      counts[PlanSkip],
+	}).Info("Dry run: resumo do plano de migração")
+}
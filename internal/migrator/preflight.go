@@ -0,0 +1,99 @@
+package migrator
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"terraform-cloud-s3-migrator/internal/terraform"
+)
+
+const (
+	// preflightSamples é o número de workspaces consultados para estimar o tamanho médio dos estados
+	preflightSamples = 5
+
+	// requestsPerWorkspace é a quantidade aproximada de requisições ao Terraform Cloud por workspace
+	// (workspace, versão atual e download do estado)
+	requestsPerWorkspace = 3
+
+	// workerThroughput é a vazão assumida de cada worker ao transferir o conteúdo, em bytes por segundo
+	workerThroughput = 8 * 1024 * 1024
+
+	// maxSuggestedWorkers acompanha o limite a partir do qual o Terraform Cloud costuma retornar HTTP 429
+	maxSuggestedWorkers = 50
+)
+
+// preflightEstimate é a estimativa de duração da migração, calculada sem baixar os estados
+type preflightEstimate struct {
+	Sampled          int
+	AverageBytes     int64
+	TotalBytes       int64
+	ETA              time.Duration
+	SuggestedWorkers int
+}
+
+// estimateMigration consulta o tamanho informado pela API de alguns estados, distribuídos pela
+// lista, e estima o volume total, a duração com concurrent_uploads e uma concorrência sugerida.
+// Retorna nil quando nenhum tamanho pôde ser obtido.
+func (m *Migrator) estimateMigration(ctx context.Context, workspaces []terraform.Workspace) *preflightEstimate {
+	var withState []terraform.Workspace
+	for _, ws := range workspaces {
+		if ws.HasState {
+			withState = append(withState, ws)
+		}
+	}
+	if len(withState) == 0 {
+		return nil
+	}
+
+	samples := min(preflightSamples, len(withState))
+	var totalSampled int64
+	var latency time.Duration
+	estimate := &preflightEstimate{}
+
+	for i := 0; i < samples; i++ {
+		ws := withState[i*len(withState)/samples]
+
+		start := time.Now()
+		size, err := m.currentStateSize(ctx, ws.ID)
+		if err != nil {
+			m.logger.WithError(err).WithField("workspace", ws.Name).Debug("Não foi possível obter o tamanho do estado para a estimativa")
+			continue
+		}
+		latency += time.Since(start)
+		totalSampled += size
+		estimate.Sampled++
+	}
+	if estimate.Sampled == 0 {
+		return nil
+	}
+
+	latency /= time.Duration(estimate.Sampled)
+	estimate.AverageBytes = totalSampled / int64(estimate.Sampled)
+	estimate.TotalBytes = estimate.AverageBytes * int64(len(withState))
+
+	// Tempo de um worker por workspace: as requisições ao Terraform Cloud mais a transferência
+	perWorkspace := requestsPerWorkspace*latency.Seconds() + float64(estimate.AverageBytes)/workerThroughput
+
+	// Limites que independem do número de workers, em workspaces por segundo
+	limit := math.Inf(1)
+	if rps := m.config.Migration.RequestsPerSecond; rps > 0 {
+		limit = math.Min(limit, rps/requestsPerWorkspace)
+	}
+	if mbps := m.config.Migration.MaxBandwidthMbps; mbps > 0 && estimate.AverageBytes > 0 {
+		limit = math.Min(limit, mbps*1000*1000/8/float64(estimate.AverageBytes))
+	}
+
+	workers := float64(m.config.Migration.ConcurrentUploads)
+	rate := math.Min(workers/perWorkspace, limit)
+	estimate.ETA = time.Duration(float64(len(withState)) / rate * float64(time.Second)).Round(time.Second)
+
+	// Mais workers do que o necessário para atingir os limites apenas aumenta as respostas HTTP 429
+	suggested := maxSuggestedWorkers
+	if !math.IsInf(limit, 1) {
+		suggested = int(math.Ceil(limit * perWorkspace))
+	}
+	estimate.SuggestedWorkers = max(1, min(suggested, maxSuggestedWorkers, len(withState)))
+
+	return estimate
+}
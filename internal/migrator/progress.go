@@ -0,0 +1,104 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// progress exibe uma linha atualizada com "\r" com o andamento da migração.
+// Não é seguro para uso concorrente: chamadores devem proteger o acesso com um mutex.
+type progress struct {
+	writer io.Writer
+	total  int
+	start  time.Time
+}
+
+// newProgress cria o indicador de progresso, retornando nil se writer for nil
+func newProgress(writer io.Writer, total int) *progress {
+	if writer == nil {
+		return nil
+	}
+
+	return &progress{
+		writer: writer,
+		total:  total,
+		start:  time.Now(),
+	}
+}
+
+// update reescreve a linha de progresso com os contadores atuais e a estimativa de término
+func (p *progress) update(successful, failed int) {
+	if p == nil {
+		return
+	}
+
+	done := successful + failed
+	eta := "--"
+	if done > 0 {
+		average := time.Since(p.start) / time.Duration(done)
+		eta = (average * time.Duration(p.total-done)).Round(time.Second).String()
+	}
+
+	fmt.Fprintf(p.writer, "\r Migrados %d/%d, %d falhas, ETA %s ", done, p.total, failed, eta)
+}
+
+// finish encerra a linha de progresso
+func (p *progress) finish() {
+	if p == nil {
+		return
+	}
+
+	fmt.Fprintln(p.writer)
+}
+
+// startProgressTicker registra no log, a cada intervalo, um resumo do andamento lido sob o mutex das
+// estatísticas, independente do batch_size. O ticker para ao chamar a função retornada ou quando o
+// contexto é cancelado. Um intervalo zero desativa o log.
+func (m *Migrator) startProgressTicker(ctx context.Context, interval time.Duration, total int, mu *sync.Mutex, stats *MigrationStats) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	start := time.Now()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				mu.Lock()
+				successful, failed := stats.Successful, stats.Failed
+				mu.Unlock()
+
+				processed := successful + failed
+				m.logger.WithFields(logrus.Fields{
+					"successful": successful,
+					"failed":     failed,
+					"remaining":  total - processed,
+					"throughput": fmt.Sprintf("%.1f/min", float64(processed)/time.Since(start).Minutes()),
+					"elapsed":    time.Since(start).Round(time.Second).String(),
+				}).Info("Andamento da migração")
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
@@ -0,0 +1,95 @@
+package migrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// progressSnapshot é o conteúdo do --progress-file: o relatório parcial da migração e se ela
+// ainda está em andamento
+type progressSnapshot struct {
+	migrationReport
+	Running   bool      `json:"running"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// startProgressFile grava, a cada intervalo, o snapshot das estatísticas em path, lido sob o mutex
+// das estatísticas, para que um dashboard externo acompanhe a execução. A gravação continua após
+// o cancelamento do contexto, enquanto os workspaces em andamento terminam, e para ao chamar a
+// função retornada. Um path vazio desativa a gravação; um intervalo zero grava apenas o snapshot
+// final.
+func (m *Migrator) startProgressFile(path string, interval time.Duration, mu *sync.Mutex, stats *MigrationStats) func() {
+	if path == "" || interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				mu.Lock()
+				content, err := marshalProgress(stats, true)
+				mu.Unlock()
+
+				if err == nil {
+					err = writeFileAtomic(path, content)
+				}
+				if err != nil {
+					m.logger.WithError(err).WithField("progress_file", path).Warn("Erro ao gravar arquivo de progresso")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// writeProgressFile grava o snapshot final das estatísticas em path, após o término da migração
+func (m *Migrator) writeProgressFile(path string, stats *MigrationStats) {
+	if path == "" {
+		return
+	}
+
+	content, err := marshalProgress(stats, false)
+	if err == nil {
+		err = writeFileAtomic(path, content)
+	}
+	if err != nil {
+		m.logger.WithError(err).WithField("progress_file", path).Warn("Erro ao gravar arquivo de progresso")
+	}
+}
+
+// marshalProgress serializa o snapshot das estatísticas. Durante a execução, a duração é
+// calculada até o momento. O chamador deve impedir escritas concorrentes em stats.
+func marshalProgress(stats *MigrationStats, running bool) ([]byte, error) {
+	snapshot := progressSnapshot{
+		migrationReport: stats.report(),
+		Running:         running,
+		UpdatedAt:       time.Now().UTC(),
+	}
+	if running {
+		snapshot.DurationSeconds = time.Since(stats.StartTime).Seconds()
+	}
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar arquivo de progresso: %w", err)
+	}
+
+	return content, nil
+}
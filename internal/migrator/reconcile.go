@@ -0,0 +1,129 @@
+package migrator
+
+import (
+	"context"
+	"sync"
+
+	"terraform-cloud-s3-migrator/internal/terraform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReconcileStatus representa a situação de um workspace em relação ao estado migrado no S3
+type ReconcileStatus string
+
+const (
+	ReconcileUpToDate ReconcileStatus = "up-to-date"
+	ReconcileStale    ReconcileStatus = "stale in S3 (will update)"
+	ReconcileMissing  ReconcileStatus = "missing"
+	ReconcileError    ReconcileStatus = "error"
+)
+
+// ReconcileResult descreve a comparação entre o serial do Terraform Cloud e o registrado no S3
+type ReconcileResult struct {
+	WorkspaceName string          `json:"workspace"`
+	S3Name        string          `json:"s3_name"`
+	Status        ReconcileStatus `json:"status"`
+	SourceSerial  int64           `json:"source_serial"`
+	TargetSerial  int64           `json:"target_serial"`
+	Error         string          `json:"error,omitempty"`
+}
+
+// Reconcile compara o serial atual de cada workspace com o metadata.json migrado para o S3.
+// Workspaces com serial maior no Terraform Cloud são reportados como desatualizados no S3.
+func (m *Migrator) Reconcile(ctx context.Context, options MigrationOptions) ([]ReconcileResult, error) {
+	if err := m.ValidateConnections(ctx); err != nil {
+		return nil, err
+	}
+
+	states, err := m.listStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Serial registrado no metadata.json de cada estado migrado (-1 se ausente), por bucket e nome no S3
+	migrated := make(map[string]int64, len(states))
+	for _, st := range states {
+		serial := int64(-1)
+		// Números em JSON são deserializados como float64
+		if value, ok := st.Metadata["serial"].(float64); ok {
+			serial = int64(value)
+		}
+		migrated[sinkName(st.destination)+"/"+st.WorkspaceName] = serial
+	}
+
+	workspaces, err := m.selectWorkspaces(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var withState []terraform.Workspace
+	for _, ws := range workspaces {
+		if ws.HasState {
+			withState = append(withState, ws)
+		}
+	}
+
+	results := make([]ReconcileResult, len(withState))
+
+	// Consultar os seriais no Terraform Cloud em paralelo, limitado por concurrent_uploads
+	sem := make(chan struct{}, m.config.Migration.ConcurrentUploads)
+	var wg sync.WaitGroup
+
+	for i, ws := range withState {
+		cleanName := m.s3Name(ws.Name)
+		results[i] = ReconcileResult{
+			WorkspaceName: ws.Name,
+			S3Name:        cleanName,
+			SourceSerial:  -1,
+			TargetSerial:  -1,
+		}
+
+		targetSerial, ok := migrated[sinkName(m.destination(ws.Name))+"/"+cleanName]
+		if !ok {
+			results[i].Status = ReconcileMissing
+			continue
+		}
+		results[i].TargetSerial = targetSerial
+
+		wg.Add(1)
+		go func(result *ReconcileResult, ws terraform.Workspace) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			serial, err := m.currentSerial(ctx, ws.ID)
+			if err != nil {
+				result.Status = ReconcileError
+				result.Error = err.Error()
+				return
+			}
+
+			result.SourceSerial = serial
+			if serial > result.TargetSerial {
+				result.Status = ReconcileStale
+			} else {
+				result.Status = ReconcileUpToDate
+			}
+		}(&results[i], ws)
+	}
+
+	wg.Wait()
+
+	for _, result := range results {
+		logger := m.logger.WithFields(logrus.Fields{
+			"workspace":     result.WorkspaceName,
+			"status":        result.Status,
+			"source_serial": result.SourceSerial,
+			"target_serial": result.TargetSerial,
+		})
+		if result.Error != "" {
+			logger.WithField("error", result.Error).Warn("Erro ao obter serial do Terraform Cloud")
+		} else {
+			logger.Debug("Workspace reconciliado")
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,120 @@
+package migrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// migrationReport é a representação JSON estável de MigrationStats
+type migrationReport struct {
+	StartTime       time.Time               `json:"start_time"`
+	EndTime         time.Time               `json:"end_time"`
+	DurationSeconds float64                 `json:"duration_seconds"`
+	Total           int                     `json:"total"`
+	Successful      int                     `json:"successful"`
+	Failed          int                     `json:"failed"`
+	Skipped         int                     `json:"skipped"`
+	Interrupted     bool                    `json:"interrupted"`
+	Aborted         bool                    `json:"aborted"`
+	AbortReason     string                  `json:"abort_reason,omitempty"`
+	TotalBytes      int64                   `json:"total_bytes"`
+	Workspaces      []workspaceResultReport `json:"workspaces"`
+	FailedItems     []FailedMigration       `json:"failed_items"`
+	FailuresByCause map[string]int          `json:"failures_by_category"`
+	Collisions      []KeyCollision          `json:"collisions"`
+	SkippedNoState  []string                `json:"skipped_no_state"`
+	SkippedTooOld   []string                `json:"skipped_too_old"`
+	SkippedRecent   []string                `json:"skipped_recent"`
+	SkippedLocked   []string                `json:"skipped_locked"`
+	Plan            []PlanEntry             `json:"plan,omitempty"`
+}
+
+type workspaceResultReport struct {
+	Workspace       string  `json:"workspace"`
+	S3Name          string  `json:"s3_name"`
+	Success         bool    `json:"success"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Bytes           int64   `json:"bytes"`
+	Serial          int     `json:"serial"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// WriteReport grava as estatísticas da migração em um arquivo JSON
+func (s *MigrationStats) WriteReport(path string) error {
+	content, err := s.MarshalReport()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório do relatório %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("erro ao gravar relatório %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// MarshalReport serializa as estatísticas da migração no formato JSON do relatório
+func (s *MigrationStats) MarshalReport() ([]byte, error) {
+	content, err := json.MarshalIndent(s.report(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar relatório: %w", err)
+	}
+
+	return content, nil
+}
+
+// report converte as estatísticas na representação do relatório, copiando os slices
+func (s *MigrationStats) report() migrationReport {
+	report := migrationReport{
+		StartTime:       s.StartTime.UTC(),
+		EndTime:         s.EndTime.UTC(),
+		DurationSeconds: s.Duration.Seconds(),
+		Total:           s.Total,
+		Successful:      s.Successful,
+		Failed:          s.Failed,
+		Skipped:         s.Skipped,
+		Interrupted:     s.Interrupted,
+		Aborted:         s.Aborted,
+		AbortReason:     s.AbortReason,
+		Workspaces:      []workspaceResultReport{},
+		FailedItems:     []FailedMigration{},
+		FailuresByCause: map[string]int{},
+		Collisions:      []KeyCollision{},
+		SkippedNoState:  []string{},
+		SkippedTooOld:   []string{},
+		SkippedRecent:   []string{},
+		SkippedLocked:   []string{},
+	}
+
+	for _, result := range s.WorkspaceResults {
+		report.TotalBytes += result.Bytes
+		report.Workspaces = append(report.Workspaces, workspaceResultReport{
+			Workspace:       result.WorkspaceName,
+			S3Name:          result.S3Name,
+			Success:         result.Success,
+			DurationSeconds: result.Duration.Seconds(),
+			Bytes:           result.Bytes,
+			Serial:          result.Serial,
+			Error:           result.Error,
+		})
+	}
+	report.FailedItems = append(report.FailedItems, s.FailedItems...)
+	for _, failure := range s.FailedItems {
+		report.FailuresByCause[failure.Category]++
+	}
+	report.Collisions = append(report.Collisions, s.Collisions...)
+	report.SkippedNoState = append(report.SkippedNoState, s.SkippedNoState...)
+	report.SkippedTooOld = append(report.SkippedTooOld, s.SkippedTooOld...)
+	report.SkippedRecent = append(report.SkippedRecent, s.SkippedRecent...)
+	report.SkippedLocked = append(report.SkippedLocked, s.SkippedLocked...)
+	report.Plan = s.Plan
+
+	return report
+}
@@ -0,0 +1,131 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-cloud-s3-migrator/internal/terraform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Rollback remove do S3 os estados enviados anteriormente pelo migrator
+func (m *Migrator) Rollback(ctx context.Context, options MigrationOptions) error {
+	if err := m.validateDestinations(ctx); err != nil {
+		return err
+	}
+
+	// Os estados podem estar no bucket padrão ou nos buckets de aws.environment_buckets
+	states, err := m.listStates(ctx)
+	if err != nil {
+		return err
+	}
+
+	mf, err := m.loadManifest(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Filtrar pelos projetos solicitados, aceitando tanto o nome original quanto o nome limpo
+	if len(options.Projects) > 0 {
+		found := make(map[string]bool)
+		var selected []destinationState
+
+		for _, st := range states {
+			originalName, _ := st.Metadata["workspace_name"].(string)
+			for _, projectName := range options.Projects {
+				entry, inManifest := mf.lookup(projectName)
+				if projectName == originalName || projectName == st.WorkspaceName || (inManifest && entry.S3Name == st.WorkspaceName) {
+					found[projectName] = true
+					selected = append(selected, st)
+					break
+				}
+			}
+		}
+
+		var notFoundProjects []string
+		for _, projectName := range options.Projects {
+			if !found[projectName] {
+				notFoundProjects = append(notFoundProjects, projectName)
+			}
+		}
+		if len(notFoundProjects) > 0 {
+			m.logger.WithField("not_found", notFoundProjects).Warn("Alguns projetos especificados não foram encontrados no S3")
+		}
+
+		return m.rollbackStates(ctx, selected, mf, options.DryRun)
+	}
+
+	return m.rollbackStates(ctx, states, mf, options.DryRun)
+}
+
+// rollbackStates remove os estados selecionados, ignorando os que não foram criados pelo migrator,
+// e retira do manifesto os workspaces removidos
+func (m *Migrator) rollbackStates(ctx context.Context, states []destinationState, mf *manifest, dryRun bool) error {
+	if len(states) == 0 {
+		m.logger.Warn("Nenhum estado encontrado no S3 para rollback")
+		return nil
+	}
+
+	var removed, skipped, failed int
+
+	for _, st := range states {
+		originalName, _ := st.Metadata["workspace_name"].(string)
+		logger := m.logger.WithFields(logrus.Fields{
+			"workspace":    originalName,
+			"s3_name":      st.WorkspaceName,
+			"state_key":    st.StateKey,
+			"metadata_key": st.MetadataKey,
+			"bucket":       sinkName(st.destination),
+		})
+
+		// Nunca remover estados que não foram enviados por esta ferramenta
+		source, _ := st.Metadata["source"].(string)
+		if source != terraform.StateSource {
+			logger.WithField("source", source).Warn("Estado não foi criado pelo migrator, pulando")
+			skipped++
+			continue
+		}
+
+		if dryRun {
+			logger.Info("Dry run: estado seria removido")
+			removed++
+			continue
+		}
+
+		if err := st.destination.DeleteState(ctx, m.config.TerraformCloud.Organization, st.WorkspaceName); err != nil {
+			logger.WithError(err).Error("Falha ao remover estado")
+			failed++
+			continue
+		}
+
+		removed++
+		mf.removeS3Name(st.WorkspaceName)
+		logger.Info("Estado removido com sucesso")
+	}
+
+	if !dryRun && removed > 0 {
+		if err := m.saveManifest(ctx, mf); err != nil {
+			m.logger.WithError(err).Error("Erro ao atualizar manifesto da migração")
+		}
+	}
+
+	mode := "Rollback"
+	if dryRun {
+		mode = "Dry run"
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"mode":    mode,
+		"total":   len(states),
+		"removed": removed,
+		"skipped": skipped,
+		"failed":  failed,
+	}).Info("Rollback finalizado")
+
+	if failed > 0 {
+		return fmt.Errorf("rollback concluído com %d falhas", failed)
+	}
+
+	return nil
+}
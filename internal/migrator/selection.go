@@ -0,0 +1,334 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"terraform-cloud-s3-migrator/internal/terraform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// workspacePattern representa um item de --projects ou --exclude, que pode ser
+// um nome exato, um glob (ex: payments-*) ou uma expressão regular
+type workspacePattern struct {
+	raw    string
+	regexp *regexp.Regexp
+}
+
+// newWorkspacePatterns compila a lista de padrões
+func newWorkspacePatterns(values []string, useRegex bool) ([]workspacePattern, error) {
+	patterns := make([]workspacePattern, 0, len(values))
+	for _, value := range values {
+		pattern := workspacePattern{raw: value}
+
+		if useRegex {
+			// A expressão precisa casar com o nome inteiro do workspace
+			re, err := regexp.Compile("^(?:" + value + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("expressão regular inválida '%s': %w", value, err)
+			}
+			pattern.regexp = re
+		} else if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("padrão glob inválido '%s': %w", value, err)
+		}
+
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, nil
+}
+
+// isPattern indica se o item deve ser comparado contra a lista completa de workspaces
+func (p workspacePattern) isPattern() bool {
+	return p.regexp != nil || strings.ContainsAny(p.raw, "*?[")
+}
+
+// match verifica se o nome do workspace corresponde ao padrão
+func (p workspacePattern) match(name string) bool {
+	if p.regexp != nil {
+		return p.regexp.MatchString(name)
+	}
+	matched, _ := path.Match(p.raw, name)
+	return matched
+}
+
+// hasPatterns indica se algum item exige a listagem completa dos workspaces
+func hasPatterns(patterns []workspacePattern) bool {
+	for _, p := range patterns {
+		if p.isPattern() {
+			return true
+		}
+	}
+	return false
+}
+
+// selectWorkspaces obtém os workspaces selecionados, por nome, por padrão ou todos da organização,
+// removendo os que correspondem à lista de exclusão
+func (m *Migrator) selectWorkspaces(ctx context.Context, options MigrationOptions) ([]terraform.Workspace, error) {
+	options.Projects = m.dedupeNames(options.Projects)
+
+	includes, err := newWorkspacePatterns(options.Projects, options.Regex)
+	if err != nil {
+		return nil, err
+	}
+
+	excludes, err := newWorkspacePatterns(options.Exclude, options.Regex)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectID string
+	if options.TFCProject != "" {
+		projects, ok := m.tfClient.(projectSource)
+		if !ok {
+			return nil, m.unsupportedSourceError("--tfc-project")
+		}
+		projectID, err = projects.GetProjectID(ctx, options.TFCProject)
+		if err != nil {
+			return nil, err
+		}
+		m.logger.WithField("tfc_project", options.TFCProject).Info("Selecionando workspaces do projeto do Terraform Cloud")
+	}
+
+	var workspaces []terraform.Workspace
+
+	if len(includes) > 0 && !hasPatterns(includes) {
+		// Selecionar apenas projetos específicos pelo nome exato
+		workspaces, err = m.lookupWorkspaces(ctx, options.Projects, options.Strict)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if len(includes) > 0 {
+			m.logger.WithField("patterns", options.Projects).Info("Selecionando workspaces por padrão")
+		} else if projectID == "" {
+			m.logger.Info("Selecionando TODOS os workspaces da organização")
+		}
+
+		allWorkspaces, err := m.tfClient.ListWorkspaces(ctx, terraform.WorkspaceFilter{
+			Tags:      options.Tags,
+			ProjectID: projectID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(includes) > 0 {
+			var unmatched []workspacePattern
+			workspaces, unmatched = matchWorkspaces(allWorkspaces, includes)
+			if err := m.checkUnmatched(unmatched, options.Strict); err != nil {
+				return nil, err
+			}
+		} else {
+			workspaces = allWorkspaces
+		}
+	}
+
+	workspaces = m.filterByTags(workspaces, options.Tags)
+	workspaces = m.filterByProject(workspaces, projectID, options.TFCProject)
+
+	return m.excludeWorkspaces(workspaces, excludes)
+}
+
+// dedupeNames remove nomes repetidos de --projects (e --projects-file), sem diferenciar maiúsculas,
+// para que o mesmo workspace não seja migrado duas vezes em paralelo para a mesma chave
+func (m *Migrator) dedupeNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if seen[key] {
+			m.logger.WithField("workspace", name).Warn("Workspace repetido em --projects, ignorando a duplicata")
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, name)
+	}
+
+	return unique
+}
+
+// filterByProject mantém apenas os workspaces do projeto do Terraform Cloud informado.
+// A listagem já é filtrada pelo Terraform Cloud, mas workspaces buscados pelo nome não são.
+func (m *Migrator) filterByProject(workspaces []terraform.Workspace, projectID, projectName string) []terraform.Workspace {
+	if projectID == "" {
+		return workspaces
+	}
+
+	var kept []terraform.Workspace
+	var skipped []string
+	for _, ws := range workspaces {
+		if ws.ProjectID == projectID {
+			kept = append(kept, ws)
+		} else {
+			skipped = append(skipped, ws.Name)
+		}
+	}
+
+	if len(skipped) > 0 {
+		m.logger.WithFields(logrus.Fields{
+			"tfc_project": projectName,
+			"workspaces":  skipped,
+		}).Info("Workspaces fora do projeto do Terraform Cloud solicitado (serão ignorados)")
+	}
+
+	return kept
+}
+
+// filterByTags mantém apenas os workspaces que possuem todas as tags solicitadas.
+// A listagem já é filtrada pelo Terraform Cloud, mas workspaces buscados pelo nome não são.
+func (m *Migrator) filterByTags(workspaces []terraform.Workspace, tags []string) []terraform.Workspace {
+	if len(tags) == 0 {
+		return workspaces
+	}
+
+	var kept []terraform.Workspace
+	var skipped []string
+	for _, ws := range workspaces {
+		if ws.HasTags(tags) {
+			kept = append(kept, ws)
+		} else {
+			skipped = append(skipped, ws.Name)
+		}
+	}
+
+	if len(skipped) > 0 {
+		m.logger.WithFields(logrus.Fields{
+			"tags":       tags,
+			"workspaces": skipped,
+		}).Info("Workspaces sem as tags solicitadas (serão ignorados)")
+	}
+
+	return kept
+}
+
+// lookupWorkspaces busca os workspaces pelo nome exato. Workspaces inexistentes são apenas
+// registrados no log, exceto com strict; demais erros (autenticação, rede) interrompem a seleção.
+func (m *Migrator) lookupWorkspaces(ctx context.Context, names []string, strict bool) ([]terraform.Workspace, error) {
+	var workspaces []terraform.Workspace
+	var notFoundProjects []string
+
+	m.logger.WithField("projects", names).Info("Selecionando projetos específicos")
+	for _, projectName := range names {
+		workspace, err := m.tfClient.GetWorkspaceByName(ctx, projectName)
+		if errors.Is(err, terraform.ErrWorkspaceNotFound) {
+			m.logger.WithField("workspace", projectName).Warn("Workspace não encontrado")
+			notFoundProjects = append(notFoundProjects, projectName)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, *workspace)
+	}
+
+	if err := m.reportNotFound(notFoundProjects, strict); err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+// matchWorkspaces seleciona os workspaces que correspondem a algum dos padrões,
+// retornando também os padrões que não corresponderam a nenhum workspace
+func matchWorkspaces(workspaces []terraform.Workspace, patterns []workspacePattern) ([]terraform.Workspace, []workspacePattern) {
+	matchCount := make([]int, len(patterns))
+	var matched []terraform.Workspace
+
+	for _, ws := range workspaces {
+		selected := false
+		for i, p := range patterns {
+			if p.match(ws.Name) {
+				matchCount[i]++
+				selected = true
+			}
+		}
+		if selected {
+			matched = append(matched, ws)
+		}
+	}
+
+	var unmatched []workspacePattern
+	for i, p := range patterns {
+		if matchCount[i] == 0 {
+			unmatched = append(unmatched, p)
+		}
+	}
+
+	return matched, unmatched
+}
+
+// checkUnmatched falha se algum glob ou expressão de --projects não corresponder a nenhum workspace.
+// Nomes exatos sem correspondência são apenas registrados no log, exceto com --strict.
+func (m *Migrator) checkUnmatched(unmatched []workspacePattern, strict bool) error {
+	var patterns, names []string
+	for _, p := range unmatched {
+		if p.isPattern() {
+			patterns = append(patterns, p.raw)
+		} else {
+			names = append(names, p.raw)
+		}
+	}
+
+	if len(patterns) > 0 {
+		return fmt.Errorf("padrões não correspondem a nenhum workspace: %s", strings.Join(patterns, ", "))
+	}
+
+	return m.reportNotFound(names, strict)
+}
+
+// reportNotFound registra os workspaces pedidos pelo nome que não foram encontrados, ou falha com --strict
+func (m *Migrator) reportNotFound(names []string, strict bool) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	if strict {
+		return fmt.Errorf("workspaces solicitados não encontrados (--strict): %s", strings.Join(names, ", "))
+	}
+
+	m.logger.WithField("not_found", names).Warn("Alguns projetos especificados não foram encontrados")
+	return nil
+}
+
+// excludeWorkspaces remove da lista os workspaces que correspondem à lista de exclusão
+func (m *Migrator) excludeWorkspaces(workspaces []terraform.Workspace, excludes []workspacePattern) ([]terraform.Workspace, error) {
+	if len(excludes) == 0 {
+		return workspaces, nil
+	}
+
+	// Uma exclusão sem correspondência não impede a migração dos demais workspaces
+	excluded, unmatched := matchWorkspaces(workspaces, excludes)
+	if len(unmatched) > 0 {
+		var raw []string
+		for _, p := range unmatched {
+			raw = append(raw, p.raw)
+		}
+		m.logger.WithField("exclude", raw).Warn("Itens de --exclude não correspondem a nenhum workspace selecionado")
+	}
+
+	excludedSet := make(map[string]bool, len(excluded))
+	var excludedNames []string
+	for _, ws := range excluded {
+		excludedSet[ws.ID] = true
+		excludedNames = append(excludedNames, ws.Name)
+	}
+
+	var kept []terraform.Workspace
+	for _, ws := range workspaces {
+		if !excludedSet[ws.ID] {
+			kept = append(kept, ws)
+		}
+	}
+
+	if len(excludedNames) > 0 {
+		m.logger.WithField("workspaces", excludedNames).Info("Workspaces excluídos da migração")
+	}
+
+	return kept, nil
+}
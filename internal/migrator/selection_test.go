@@ -0,0 +1,69 @@
+package migrator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"terraform-cloud-s3-migrator/internal/terraform"
+)
+
+func TestSelectWorkspaces(t *testing.T) {
+	source := &fakeSource{workspaces: []terraform.Workspace{
+		{ID: "ws-1", Name: "payments-api", HasState: true},
+		{ID: "ws-2", Name: "payments-web", HasState: true},
+		{ID: "ws-3", Name: "network", HasState: true},
+	}}
+
+	tests := []struct {
+		name    string
+		options MigrationOptions
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "glob e nome exato",
+			options: MigrationOptions{Projects: []string{"payments-*", "network"}},
+			want:    []string{"payments-api", "payments-web", "network"},
+		},
+		{
+			name:    "nome exato inexistente junto de glob",
+			options: MigrationOptions{Projects: []string{"payments-*", "netwrok"}},
+			want:    []string{"payments-api", "payments-web"},
+		},
+		{
+			name:    "nome exato inexistente junto de glob com --strict",
+			options: MigrationOptions{Projects: []string{"payments-*", "netwrok"}, Strict: true},
+			wantErr: true,
+		},
+		{
+			name:    "nome exato inexistente com --strict",
+			options: MigrationOptions{Projects: []string{"network", "netwrok"}, Strict: true},
+			wantErr: true,
+		},
+		{
+			name:    "glob sem correspondência",
+			options: MigrationOptions{Projects: []string{"billing-*", "network"}},
+			wantErr: true,
+		},
+		{
+			name:    "exclusão sem correspondência",
+			options: MigrationOptions{Projects: []string{"payments-*"}, Exclude: []string{"payments-legacy", "payments-web"}},
+			want:    []string{"payments-api"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMigrator(t, source, &fakeSink{})
+
+			got, err := m.selectWorkspaces(context.Background(), tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("erro = %v, esperado erro: %v", err, tt.wantErr)
+			}
+			if names := workspaceNames(got); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("workspaces = %v, esperado %v", names, tt.want)
+			}
+		})
+	}
+}
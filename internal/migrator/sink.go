@@ -0,0 +1,111 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"terraform-cloud-s3-migrator/internal/s3client"
+)
+
+// StateSink é o destino dos estados migrados. Hoje é implementado por s3client.Client;
+// novos backends (GCS, Azure Blob, diretório local) devem implementar esta interface.
+type StateSink interface {
+	UploadState(ctx context.Context, organization, workspaceName string, body io.Reader, metadata map[string]interface{}) error
+	CheckStateExists(ctx context.Context, organization, workspaceName string) (bool, error)
+	DeleteState(ctx context.Context, organization, workspaceName string) error
+	ValidateConnection(ctx context.Context) error
+}
+
+// As interfaces abaixo são capacidades opcionais de um StateSink, consultadas por type assertion.
+// Um destino que não implementa uma delas tem o recurso correspondente ignorado ou reportado como
+// não suportado, sem impedir a migração dos estados.
+
+// namedSink identifica o destino em logs, no manifesto e na detecção de colisões
+type namedSink interface {
+	Bucket() string
+	StateKey(organization, workspaceName string) string
+}
+
+// bucketManager cria o bucket de destino e consulta o seu versionamento
+type bucketManager interface {
+	EnsureBucket(ctx context.Context) (bool, error)
+	VersioningEnabled(ctx context.Context) (bool, string, error)
+}
+
+// healthProber verifica a criptografia padrão e as permissões de escrita do destino
+type healthProber interface {
+	CheckEncryption(ctx context.Context) (string, error)
+	ProbeWrite(ctx context.Context) error
+}
+
+// lockWriter grava o digest do estado na tabela de lock do backend S3
+type lockWriter interface {
+	CreateLockEntry(ctx context.Context, organization, workspaceName, digest string) error
+}
+
+// artifactWriter grava os arquivos gravados ao lado do estado: metadata.json, variables.json e histórico
+type artifactWriter interface {
+	UploadMetadata(ctx context.Context, organization, workspaceName string, metadata map[string]interface{}) error
+	UploadVariables(ctx context.Context, organization, workspaceName string, content []byte) error
+	UploadStateVersion(ctx context.Context, organization, workspaceName string, serial int64, body io.Reader) error
+}
+
+// stateLister lista os estados já migrados para o destino
+type stateLister interface {
+	ListStates(ctx context.Context, organization string) ([]s3client.StateObject, error)
+	ListMigratedStates(ctx context.Context, organization string) ([]s3client.MigratedState, error)
+}
+
+// stateReader lê de volta um estado migrado e o seu metadata.json
+type stateReader interface {
+	GetStateMetadata(ctx context.Context, organization, workspaceName string) (map[string]interface{}, error)
+	DownloadState(ctx context.Context, organization, workspaceName string) ([]byte, error)
+}
+
+// manifestStore grava e lê o manifesto da migração no destino
+type manifestStore interface {
+	UploadManifest(ctx context.Context, organization string, content []byte) error
+	DownloadManifest(ctx context.Context, organization string) ([]byte, error)
+}
+
+var (
+	_ StateSink      = (*s3client.Client)(nil)
+	_ namedSink      = (*s3client.Client)(nil)
+	_ bucketManager  = (*s3client.Client)(nil)
+	_ healthProber   = (*s3client.Client)(nil)
+	_ lockWriter     = (*s3client.Client)(nil)
+	_ artifactWriter = (*s3client.Client)(nil)
+	_ stateLister    = (*s3client.Client)(nil)
+	_ stateReader    = (*s3client.Client)(nil)
+	_ manifestStore  = (*s3client.Client)(nil)
+)
+
+// WithStateSink substitui o client do S3 do bucket padrão criado a partir da configuração.
+// Os destinos de aws.environment_buckets continuam sendo criados a partir da configuração.
+func WithStateSink(sink StateSink) Option {
+	return func(m *Migrator) {
+		m.sink = sink
+	}
+}
+
+// sinkName identifica o destino: o bucket, quando informado, ou o tipo da implementação
+func sinkName(sink StateSink) string {
+	if named, ok := sink.(namedSink); ok {
+		return named.Bucket()
+	}
+	return fmt.Sprintf("%T", sink)
+}
+
+// sinkStateKey retorna a chave do estado no destino, ou vazio se o destino não a informa
+func sinkStateKey(sink StateSink, organization, workspaceName string) string {
+	if named, ok := sink.(namedSink); ok {
+		return named.StateKey(organization, workspaceName)
+	}
+	return ""
+}
+
+// unsupportedError indica que o destino não implementa a capacidade necessária para a operação
+func unsupportedError(sink StateSink, operation string) error {
+	return fmt.Errorf("o destino %s não suporta %s", sinkName(sink), operation)
+}
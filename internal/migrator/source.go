@@ -0,0 +1,126 @@
+package migrator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"terraform-cloud-s3-migrator/internal/terraform"
+)
+
+// TerraformSource é a origem dos workspaces e estados migrados. Hoje é implementada por
+// terraform.Client; uma implementação em memória permite exercitar o Migrator sem o Terraform Cloud.
+type TerraformSource interface {
+	ListWorkspaces(ctx context.Context, filter terraform.WorkspaceFilter) ([]terraform.Workspace, error)
+	GetWorkspaceState(ctx context.Context, workspaceID string) (*terraform.StateData, error)
+	GetWorkspaceByName(ctx context.Context, name string) (*terraform.Workspace, error)
+	ValidateConnection(ctx context.Context) error
+}
+
+// As interfaces abaixo são capacidades opcionais de um TerraformSource, consultadas por type
+// assertion. Sem elas o Migrator recorre a GetWorkspaceState ou reporta o recurso como não suportado.
+
+// workspacePager lista uma página de workspaces por vez, para organizações muito grandes
+type workspacePager interface {
+	ListWorkspacesPage(ctx context.Context, page, size int) (*terraform.WorkspacePage, error)
+}
+
+// stateStreamer abre o estado atual como stream, sem carregá-lo em memória
+type stateStreamer interface {
+	OpenWorkspaceState(ctx context.Context, workspaceID string) (*terraform.StateData, io.ReadCloser, error)
+}
+
+// stateInspector consulta a versão atual do estado sem baixá-lo
+type stateInspector interface {
+	GetCurrentStateSize(ctx context.Context, workspaceID string) (int64, error)
+	GetCurrentSerial(ctx context.Context, workspaceID string) (int64, error)
+	GetCurrentStateVersion(ctx context.Context, workspaceID string) (*terraform.StateVersion, error)
+}
+
+// historySource lista e abre as versões anteriores do estado
+type historySource interface {
+	ListStateVersions(ctx context.Context, workspaceName string) ([]terraform.StateVersion, error)
+	OpenStateVersion(ctx context.Context, workspaceName string, version terraform.StateVersion) (io.ReadCloser, error)
+}
+
+// variableSource lista as variáveis do workspace
+type variableSource interface {
+	GetWorkspaceVariables(ctx context.Context, workspaceID string) ([]terraform.Variable, error)
+}
+
+// projectSource resolve o ID de um projeto do Terraform Cloud pelo nome
+type projectSource interface {
+	GetProjectID(ctx context.Context, name string) (string, error)
+}
+
+var (
+	_ TerraformSource = (*terraform.Client)(nil)
+	_ workspacePager  = (*terraform.Client)(nil)
+	_ stateStreamer   = (*terraform.Client)(nil)
+	_ stateInspector  = (*terraform.Client)(nil)
+	_ historySource   = (*terraform.Client)(nil)
+	_ variableSource  = (*terraform.Client)(nil)
+	_ projectSource   = (*terraform.Client)(nil)
+)
+
+// Option personaliza a criação do Migrator
+type Option func(*Migrator)
+
+// WithTerraformSource substitui o client do Terraform Cloud criado a partir da configuração
+func WithTerraformSource(source TerraformSource) Option {
+	return func(m *Migrator) {
+		m.tfClient = source
+	}
+}
+
+// unsupportedSourceError indica que a origem não implementa a capacidade necessária para a operação
+func (m *Migrator) unsupportedSourceError(operation string) error {
+	return fmt.Errorf("a origem dos workspaces (%T) não suporta %s", m.tfClient, operation)
+}
+
+// openWorkspaceState abre o estado atual do workspace. Origens sem streaming têm o estado
+// carregado em memória por GetWorkspaceState.
+func (m *Migrator) openWorkspaceState(ctx context.Context, workspaceID string) (*terraform.StateData, io.ReadCloser, error) {
+	if streamer, ok := m.tfClient.(stateStreamer); ok {
+		return streamer.OpenWorkspaceState(ctx, workspaceID)
+	}
+
+	stateData, err := m.tfClient.GetWorkspaceState(ctx, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	stateData.Size = int64(len(stateData.StateContent))
+	return stateData, io.NopCloser(bytes.NewReader(stateData.StateContent)), nil
+}
+
+// currentSerial retorna o serial atual do estado, baixando-o se a origem não o informa diretamente
+func (m *Migrator) currentSerial(ctx context.Context, workspaceID string) (int64, error) {
+	if inspector, ok := m.tfClient.(stateInspector); ok {
+		return inspector.GetCurrentSerial(ctx, workspaceID)
+	}
+
+	stateData, err := m.tfClient.GetWorkspaceState(ctx, workspaceID)
+	if err != nil {
+		return 0, err
+	}
+	return int64(stateData.Version), nil
+}
+
+// currentStateVersion retorna a versão atual do estado, com a data de criação
+func (m *Migrator) currentStateVersion(ctx context.Context, workspaceID string) (*terraform.StateVersion, error) {
+	inspector, ok := m.tfClient.(stateInspector)
+	if !ok {
+		return nil, m.unsupportedSourceError("a consulta da versão atual do estado")
+	}
+	return inspector.GetCurrentStateVersion(ctx, workspaceID)
+}
+
+// currentStateSize retorna o tamanho do estado atual sem baixá-lo
+func (m *Migrator) currentStateSize(ctx context.Context, workspaceID string) (int64, error) {
+	inspector, ok := m.tfClient.(stateInspector)
+	if !ok {
+		return 0, m.unsupportedSourceError("a consulta do tamanho do estado")
+	}
+	return inspector.GetCurrentStateSize(ctx, workspaceID)
+}
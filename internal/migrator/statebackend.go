@@ -0,0 +1,218 @@
+package migrator
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// remoteBackendTypes são os tipos de backend que apontam para o Terraform Cloud
+var remoteBackendTypes = map[string]bool{"remote": true, "cloud": true}
+
+// backendStripper remove, durante o streaming, o membro "backend" do nível superior do estado quando
+// ele aponta para o Terraform Cloud. Os demais bytes são repassados sem modificação: apenas o membro
+// "backend" (e a vírgula que o separa) é retido em memória até se saber se deve ser removido.
+type backendStripper struct {
+	source io.Reader
+	out    bytes.Buffer
+	err    error
+
+	started  bool // O primeiro caractere não branco já foi lido
+	disabled bool // O conteúdo não é um objeto JSON; tudo é repassado
+	depth    int
+	inString bool
+	escape   bool
+
+	// Membro do nível superior em leitura: retido até a chave ser conhecida e, para "backend",
+	// até o fim do valor
+	holding   bool
+	held      []byte
+	inKey     bool
+	keyDone   bool
+	key       []byte
+	first     bool // O membro é o primeiro do objeto, sem vírgula antes
+	dropComma bool // O membro anterior, o primeiro do objeto, foi removido
+
+	// Chamado quando o bloco é removido
+	onStrip func()
+}
+
+// newBackendStripper cria o reader que remove o bloco backend do Terraform Cloud de source
+func newBackendStripper(source io.Reader, onStrip func()) *backendStripper {
+	return &backendStripper{source: source, onStrip: onStrip}
+}
+
+func (s *backendStripper) Read(p []byte) (int, error) {
+	buf := make([]byte, len(p))
+	for s.out.Len() == 0 && s.err == nil {
+		n, err := s.source.Read(buf)
+		for _, c := range buf[:n] {
+			s.process(c)
+		}
+		if err != nil {
+			// Um membro incompleto no fim do stream é repassado; a validação rejeita o JSON truncado
+			s.flush()
+			s.err = err
+		}
+	}
+
+	if s.out.Len() > 0 {
+		return s.out.Read(p)
+	}
+	return 0, s.err
+}
+
+// process trata um byte do stream, acompanhando strings e o nível de aninhamento
+func (s *backendStripper) process(c byte) {
+	if s.disabled {
+		s.out.WriteByte(c)
+		return
+	}
+
+	if !s.started {
+		if isJSONSpace(c) {
+			s.out.WriteByte(c)
+			return
+		}
+		s.started = true
+		if c != '{' {
+			s.disabled = true
+			s.out.WriteByte(c)
+			return
+		}
+	}
+
+	if s.inString {
+		s.emit(c)
+		switch {
+		case s.escape:
+			s.escape = false
+		case c == '\\':
+			s.escape = true
+		case c == '"':
+			s.inString = false
+			if s.inKey {
+				s.inKey = false
+				s.keyDone = true
+				if string(s.key) != "backend" {
+					s.flush()
+				}
+			}
+		default:
+			if s.inKey {
+				s.key = append(s.key, c)
+			}
+		}
+		return
+	}
+
+	switch c {
+	case '"':
+		s.inString = true
+		if s.holding && !s.keyDone && s.depth == 1 {
+			s.inKey = true
+		}
+		s.emit(c)
+	case '{', '[':
+		s.emit(c)
+		s.depth++
+		if s.depth == 1 {
+			s.startMember(true)
+		}
+	case '}', ']':
+		if s.depth == 1 {
+			s.finishMember()
+		}
+		s.depth--
+		s.emit(c)
+	case ',':
+		if s.depth == 1 {
+			s.finishMember()
+			s.startMember(false)
+		}
+		s.emit(c)
+	default:
+		s.emit(c)
+	}
+}
+
+// emit grava o byte no membro retido ou diretamente na saída
+func (s *backendStripper) emit(c byte) {
+	if s.holding {
+		s.held = append(s.held, c)
+		return
+	}
+	s.out.WriteByte(c)
+}
+
+// startMember passa a reter um novo membro do nível superior
+func (s *backendStripper) startMember(first bool) {
+	s.holding = true
+	s.held = s.held[:0]
+	s.key = s.key[:0]
+	s.inKey = false
+	s.keyDone = false
+	s.first = first
+}
+
+// finishMember decide, ao fim de um membro retido, se ele é o bloco a remover
+func (s *backendStripper) finishMember() {
+	if !s.holding {
+		return
+	}
+
+	if s.keyDone && string(s.key) == "backend" && isRemoteBackend(s.held) {
+		// Mantém apenas os espaços após o valor, para preservar a formatação do restante
+		trimmed := bytes.TrimRight(s.held, " \t\r\n")
+		s.out.Write(s.held[len(trimmed):])
+		s.dropComma = s.first
+		s.holding = false
+		if s.onStrip != nil {
+			s.onStrip()
+		}
+		return
+	}
+
+	s.flush()
+}
+
+// flush repassa o membro retido, omitindo a vírgula inicial se o primeiro membro foi removido
+func (s *backendStripper) flush() {
+	if !s.holding {
+		return
+	}
+
+	held := s.held
+	if s.dropComma && len(held) > 0 && held[0] == ',' {
+		held = held[1:]
+		s.dropComma = false
+	}
+	s.out.Write(held)
+	s.holding = false
+}
+
+// isRemoteBackend indica se o membro retido ("backend": {...}) aponta para o Terraform Cloud
+func isRemoteBackend(member []byte) bool {
+	member = bytes.TrimLeft(member, ", \t\r\n")
+
+	var wrapper struct {
+		Backend struct {
+			Type string `json:"type"`
+		} `json:"backend"`
+	}
+	if err := json.Unmarshal(append(append([]byte{'{'}, member...), '}'), &wrapper); err != nil {
+		return false
+	}
+	return remoteBackendTypes[wrapper.Backend.Type]
+}
+
+// isJSONSpace indica se o byte é um espaço em branco do JSON
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// stripRemoteBackend aplica o backendStripper a um estado em memória. Usado pelo verify para
+// reproduzir o conteúdo enviado com --rewrite-backend.
+func stripRemoteBackend(content []byte) ([]byte, error) {
+	return io.ReadAll(newBackendStripper(bytes.NewReader(content), nil))
+}
@@ -0,0 +1,70 @@
+package migrator
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStripRemoteBackend(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     string
+		stripped bool
+	}{
+		{
+			name:     "backend no meio",
+			input:    "{\n  \"version\": 4,\n  \"backend\": {\"type\": \"remote\", \"config\": {\"a\": \"}\"}},\n  \"serial\": 3\n}\n",
+			want:     "{\n  \"version\": 4,\n  \"serial\": 3\n}\n",
+			stripped: true,
+		},
+		{
+			name:     "backend primeiro",
+			input:    `{"backend":{"config":{},"type":"cloud"},"version":4,"serial":1}`,
+			want:     `{"version":4,"serial":1}`,
+			stripped: true,
+		},
+		{
+			name:     "backend por último",
+			input:    "{\n  \"version\": 4,\n  \"backend\": {\"type\": \"remote\"}\n}",
+			want:     "{\n  \"version\": 4\n}",
+			stripped: true,
+		},
+		{
+			name:  "backend s3 mantido",
+			input: `{"version":4,"backend":{"type":"s3"},"serial":1}`,
+			want:  `{"version":4,"backend":{"type":"s3"},"serial":1}`,
+		},
+		{
+			name:  "backend aninhado mantido",
+			input: `{"version":4,"outputs":{"backend":{"value":{"type":"remote"}}},"lineage":"a,\"backend\""}`,
+			want:  `{"version":4,"outputs":{"backend":{"value":{"type":"remote"}}},"lineage":"a,\"backend\""}`,
+		},
+		{
+			name:  "conteúdo que não é objeto",
+			input: `[1,2]`,
+			want:  `[1,2]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stripped := false
+			// Leituras de um byte por vez exercitam membros divididos entre chamadas
+			reader := newBackendStripper(iotest.OneByteReader(bytes.NewReader([]byte(tt.input))), func() { stripped = true })
+
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("conteúdo = %q, esperado %q", got, tt.want)
+			}
+			if stripped != tt.stripped {
+				t.Errorf("stripped = %v, esperado %v", stripped, tt.stripped)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package migrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StateDiff resume as diferenças entre o estado do Terraform Cloud (origem) e o do S3 (destino)
+// em nível de recurso, sem comparar atributos linha a linha
+type StateDiff struct {
+	SourceSerial    int64
+	TargetSerial    int64
+	SourceLineage   string
+	TargetLineage   string
+	SourceResources int
+	TargetResources int
+	Added           []string // Endereços presentes no Terraform Cloud e ausentes no S3
+	Removed         []string // Endereços presentes no S3 e ausentes no Terraform Cloud
+}
+
+// diffState é o subconjunto do arquivo de estado usado na comparação
+type diffState struct {
+	Serial    int64  `json:"serial"`
+	Lineage   string `json:"lineage"`
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey json.RawMessage `json:"index_key"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// diffStates compara os dois estados e retorna serial, lineage, quantidade de recursos
+// e os endereços adicionados ou removidos
+func diffStates(source, target []byte) (*StateDiff, error) {
+	var sourceState, targetState diffState
+	if err := json.Unmarshal(source, &sourceState); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar estado do Terraform Cloud: %w", err)
+	}
+	if err := json.Unmarshal(target, &targetState); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar estado do S3: %w", err)
+	}
+
+	sourceAddresses := sourceState.addresses()
+	targetAddresses := targetState.addresses()
+
+	diff := &StateDiff{
+		SourceSerial:    sourceState.Serial,
+		TargetSerial:    targetState.Serial,
+		SourceLineage:   sourceState.Lineage,
+		TargetLineage:   targetState.Lineage,
+		SourceResources: len(sourceAddresses),
+		TargetResources: len(targetAddresses),
+	}
+
+	for address := range sourceAddresses {
+		if !targetAddresses[address] {
+			diff.Added = append(diff.Added, address)
+		}
+	}
+	for address := range targetAddresses {
+		if !sourceAddresses[address] {
+			diff.Removed = append(diff.Removed, address)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+
+	return diff, nil
+}
+
+// addresses retorna os endereços de todas as instâncias de recursos do estado,
+// no mesmo formato de "terraform state list" (ex: module.app.aws_instance.web[0])
+func (s diffState) addresses() map[string]bool {
+	addresses := make(map[string]bool)
+	for _, resource := range s.Resources {
+		var address strings.Builder
+		if resource.Module != "" {
+			address.WriteString(resource.Module + ".")
+		}
+		if resource.Mode == "data" {
+			address.WriteString("data.")
+		}
+		address.WriteString(resource.Type + "." + resource.Name)
+
+		for _, instance := range resource.Instances {
+			key := strings.TrimSpace(string(instance.IndexKey))
+			if key == "" || key == "null" {
+				addresses[address.String()] = true
+			} else {
+				addresses[address.String()+"["+key+"]"] = true
+			}
+		}
+	}
+	return addresses
+}
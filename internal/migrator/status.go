@@ -0,0 +1,106 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"terraform-cloud-s3-migrator/internal/config"
+	"terraform-cloud-s3-migrator/internal/s3client"
+	"terraform-cloud-s3-migrator/internal/terraform"
+)
+
+// StatusReport agrupa os workspaces da organização conforme a situação da migração
+type StatusReport struct {
+	Migrated []string          `json:"migrated"`
+	Pending  []string          `json:"pending"`
+	Skipped  []string          `json:"skipped"`
+	Errors   []FailedMigration `json:"errors,omitempty"`
+}
+
+// Status verifica quais workspaces com estado já foram migrados para o S3
+func (m *Migrator) Status(ctx context.Context) (*StatusReport, error) {
+	if err := m.ValidateConnections(ctx); err != nil {
+		return nil, err
+	}
+
+	workspaces, err := m.tfClient.ListWorkspaces(ctx, terraform.WorkspaceFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar workspaces: %w", err)
+	}
+
+	report := &StatusReport{}
+	exists := make([]bool, len(workspaces))
+	errs := make([]error, len(workspaces))
+
+	// Verificar a existência no S3 em paralelo, limitado por concurrent_uploads
+	sem := make(chan struct{}, m.config.Migration.ConcurrentUploads)
+	var wg sync.WaitGroup
+
+	for i, ws := range workspaces {
+		if !ws.HasState {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, ws terraform.Workspace) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			cleanName := m.s3Name(ws.Name)
+			exists[i], errs[i] = m.destination(ws.Name).CheckStateExists(ctx, m.config.TerraformCloud.Organization, cleanName)
+		}(i, ws)
+	}
+
+	wg.Wait()
+
+	// Montar o relatório na ordem original da listagem
+	for i, ws := range workspaces {
+		switch {
+		case !ws.HasState:
+			report.Skipped = append(report.Skipped, ws.Name)
+		case errs[i] != nil:
+			m.logger.WithError(errs[i]).WithField("workspace", ws.Name).Warn("Erro ao verificar existência no S3")
+			report.Errors = append(report.Errors, FailedMigration{
+				WorkspaceName: ws.Name,
+				Error:         errs[i].Error(),
+			})
+		case exists[i]:
+			report.Migrated = append(report.Migrated, ws.Name)
+		default:
+			report.Pending = append(report.Pending, ws.Name)
+		}
+	}
+
+	return report, nil
+}
+
+// ListMigratedStates lista os estados já gravados em todos os buckets de destino, sem acessar o Terraform Cloud
+func ListMigratedStates(ctx context.Context, cfg *config.Config) ([]s3client.MigratedState, error) {
+	m, err := newStorageMigrator(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []s3client.MigratedState
+	for _, destination := range m.destinations() {
+		if err := destination.ValidateConnection(ctx); err != nil {
+			return nil, &ConnectionError{Err: fmt.Errorf("falha na validação do S3 (bucket %s): %w", sinkName(destination), err)}
+		}
+
+		lister, ok := destination.(stateLister)
+		if !ok {
+			return nil, unsupportedError(destination, "a listagem dos estados migrados")
+		}
+
+		found, err := lister.ListMigratedStates(ctx, m.config.TerraformCloud.Organization)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, found...)
+	}
+
+	return states, nil
+}
@@ -0,0 +1,28 @@
+package migrator
+
+import "io"
+
+// countingReader conta os bytes lidos de um stream
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+// downloadReader marca os erros de leitura do estado baixado como falhas de download
+type downloadReader struct {
+	io.ReadCloser
+}
+
+func (r downloadReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = withCategory(CategoryDownload, err)
+	}
+	return n, err
+}
@@ -0,0 +1,245 @@
+package migrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// stateIdentity reúne os campos que o backend S3 usa para reconhecer o estado: o lineage identifica
+// o estado e o serial a versão. Ambos precisam chegar ao S3 intactos para o backend não considerar
+// o estado reiniciado.
+type stateIdentity struct {
+	Serial    int64
+	HasSerial bool
+	Lineage   string
+
+	// Instâncias de recursos e outputs do estado, registradas nos metadados
+	Resources int
+	Outputs   int
+
+	// Tipo do bloco "backend" embutido no estado, se houver (ex: remote, cloud)
+	Backend string
+}
+
+// stateValidator valida, à medida que o stream é lido, se o conteúdo é um estado do Terraform.
+// Se o conteúdo for inválido a leitura falha, abortando o upload antes que o objeto seja gravado.
+// Os bytes são repassados sem modificação; o conteúdo nunca é reserializado.
+type stateValidator struct {
+	source   io.Reader
+	pipe     *io.PipeWriter
+	result   chan error
+	identity stateIdentity
+	onValid  func(stateIdentity)
+
+	// Apenas conta recursos e outputs, sem nunca falhar a leitura (--skip-validation)
+	lenient bool
+
+	// Resultado do primeiro EOF, repetido nas leituras seguintes
+	done bool
+	err  error
+}
+
+// newStateValidator inicia a validação do stream, que também confere se o serial do estado é igual
+// a expectedSerial (o serial da versão no Terraform Cloud). onValid, se informado, recebe o lineage
+// e o serial ao fim de um stream válido. O chamador deve chamar Close ao terminar.
+func newStateValidator(source io.Reader, expectedSerial int64, onValid func(stateIdentity)) *stateValidator {
+	v := &stateValidator{source: source, onValid: onValid}
+	v.start(expectedSerial)
+	return v
+}
+
+// newStateCounter percorre o stream com o mesmo leitor da validação, mas sem rejeitar o conteúdo:
+// onCounted recebe as contagens apenas se o JSON puder ser percorrido até o fim. Usado com
+// --skip-validation, para que o metadata.json continue registrando recursos e outputs.
+func newStateCounter(source io.Reader, onCounted func(stateIdentity)) *stateValidator {
+	v := &stateValidator{source: source, onValid: onCounted, lenient: true}
+	v.start(0)
+	return v
+}
+
+// start lê o JSON em segundo plano, alimentado pelo pipe a cada Read
+func (v *stateValidator) start(expectedSerial int64) {
+	reader, writer := io.Pipe()
+	v.pipe = writer
+	v.result = make(chan error, 1)
+
+	go func() {
+		identity, err := validateState(reader)
+		if err == nil && !v.lenient && identity.HasSerial && identity.Serial != expectedSerial {
+			err = fmt.Errorf("serial %d no conteúdo difere do serial %d da versão no Terraform Cloud", identity.Serial, expectedSerial)
+		}
+		if err != nil {
+			err = withCategory(CategoryValidation, fmt.Errorf("estado inválido: %w", err))
+		}
+		v.identity = identity
+		// Desbloqueia escritas pendentes caso a validação termine antes do fim do stream
+		reader.CloseWithError(err)
+		v.result <- err
+	}()
+}
+
+func (v *stateValidator) Read(p []byte) (int, error) {
+	if v.done {
+		return 0, v.err
+	}
+
+	n, err := v.source.Read(p)
+	if n > 0 {
+		// Sem validação, o fim antecipado da leitura do JSON não interrompe o stream
+		if _, werr := v.pipe.Write(p[:n]); werr != nil && !v.lenient {
+			return 0, werr
+		}
+	}
+
+	if errors.Is(err, io.EOF) {
+		v.pipe.Close()
+		verr := <-v.result
+		v.done, v.err = true, err
+		if verr != nil && !v.lenient {
+			v.err = verr
+			return 0, verr
+		}
+		if verr == nil && v.onValid != nil {
+			v.onValid(v.identity)
+		}
+	} else if err != nil {
+		v.pipe.CloseWithError(err)
+	}
+
+	return n, err
+}
+
+// Close encerra a validação caso o stream não tenha sido lido até o fim
+func (v *stateValidator) Close() error {
+	return v.pipe.CloseWithError(io.ErrClosedPipe)
+}
+
+// validateState percorre o JSON sem carregá-lo inteiro em memória e verifica se é um objeto
+// com as chaves "version" e "terraform_version" de um arquivo de estado do Terraform.
+// Retorna o lineage e o serial encontrados no nível superior do objeto e conta recursos e outputs.
+func validateState(r io.Reader) (stateIdentity, error) {
+	var identity stateIdentity
+
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return identity, fmt.Errorf("conteúdo não é JSON (possível página de erro HTML): %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return identity, fmt.Errorf("conteúdo não é um objeto JSON")
+	}
+
+	keys := make(map[string]bool)
+	// Pilha dos objetos e arrays abertos; a base é o objeto do estado
+	stack := []jsonFrame{{object: true, expectKey: true}}
+
+	for len(stack) > 0 {
+		token, err := decoder.Token()
+		if err != nil {
+			return identity, fmt.Errorf("JSON malformado: %w", err)
+		}
+
+		top := &stack[len(stack)-1]
+		delim, isDelim := token.(json.Delim)
+
+		switch {
+		case isDelim && (delim == '{' || delim == '['):
+			if isInstance(stack) {
+				identity.Resources++
+			}
+			stack = append(stack, jsonFrame{object: delim == '{', expectKey: true, name: top.key})
+		case isDelim:
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				stack[len(stack)-1].valueDone()
+			}
+		case top.object && top.expectKey:
+			top.key, _ = token.(string)
+			top.expectKey = false
+			if len(stack) == 1 {
+				keys[top.key] = true
+			} else if len(stack) == 2 && top.name == "outputs" {
+				identity.Outputs++
+			}
+		default:
+			if len(stack) == 1 {
+				if err := identity.capture(top.key, token); err != nil {
+					return identity, err
+				}
+			} else if len(stack) == 2 && top.name == "backend" && top.key == "type" {
+				identity.Backend, _ = token.(string)
+			}
+			top.valueDone()
+		}
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return identity, fmt.Errorf("conteúdo adicional após o objeto JSON")
+	}
+
+	for _, key := range []string{"version", "terraform_version"} {
+		if !keys[key] {
+			return identity, fmt.Errorf("chave obrigatória %q ausente", key)
+		}
+	}
+
+	return identity, nil
+}
+
+// jsonFrame é um objeto ou array aberto durante a leitura do estado
+type jsonFrame struct {
+	object    bool
+	expectKey bool
+	key       string // Última chave lida, quando o frame é um objeto
+	name      string // Chave sob a qual o frame está no objeto pai
+}
+
+// valueDone marca o fim de um valor do frame; em objetos, o próximo token é uma chave
+func (f *jsonFrame) valueDone() {
+	if f.object {
+		f.expectKey = true
+	}
+}
+
+// isInstance indica se o próximo objeto aberto é uma instância em resources[].instances[],
+// contada como um recurso no mesmo formato de "terraform state list"
+func isInstance(stack []jsonFrame) bool {
+	return len(stack) == 4 && stack[1].name == "resources" && !stack[1].object && stack[3].name == "instances" && !stack[3].object
+}
+
+// record grava nos metadados o lineage e as contagens de recursos e outputs
+func (id stateIdentity) record(metadata map[string]interface{}) {
+	if id.Lineage != "" {
+		metadata["lineage"] = id.Lineage
+	}
+	metadata["resource_count"] = id.Resources
+	metadata["output_count"] = id.Outputs
+}
+
+// capture registra o valor de "serial" ou "lineage" do nível superior do estado
+func (id *stateIdentity) capture(key string, value json.Token) error {
+	switch key {
+	case "serial":
+		number, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("serial não é numérico")
+		}
+		serial, err := number.Int64()
+		if err != nil {
+			return fmt.Errorf("serial inválido: %w", err)
+		}
+		id.Serial = serial
+		id.HasSerial = true
+	case "lineage":
+		lineage, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("lineage não é uma string")
+		}
+		id.Lineage = lineage
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package migrator
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+const validState = `{"version":4,"terraform_version":"1.5.7","serial":3,"lineage":"abc",` +
+	`"outputs":{"a":{"value":1},"b":{"value":2}},` +
+	`"resources":[{"instances":[{"attributes":{}},{"attributes":{}}]},{"instances":[{"attributes":{}}]}]}`
+
+func TestStateCounter(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantCounted   bool
+		wantResources int
+		wantOutputs   int
+	}{
+		{name: "estado válido", input: validState, wantCounted: true, wantResources: 3, wantOutputs: 2},
+		{name: "página de erro HTML", input: "<html><body>502 Bad Gateway</body></html>"},
+		{name: "JSON truncado", input: validState[:40]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var counted *stateIdentity
+			counter := newStateCounter(iotest.OneByteReader(bytes.NewReader([]byte(tt.input))), func(identity stateIdentity) {
+				counted = &identity
+			})
+			defer counter.Close()
+
+			// Sem validação o conteúdo é sempre repassado, mesmo quando não é um estado
+			got, err := io.ReadAll(counter)
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if string(got) != tt.input {
+				t.Errorf("conteúdo repassado = %q, esperado %q", got, tt.input)
+			}
+
+			if (counted != nil) != tt.wantCounted {
+				t.Fatalf("contagem registrada = %v, esperado %v", counted != nil, tt.wantCounted)
+			}
+			if counted != nil && (counted.Resources != tt.wantResources || counted.Outputs != tt.wantOutputs) {
+				t.Errorf("contagens = %d recursos e %d outputs, esperado %d e %d", counted.Resources, counted.Outputs, tt.wantResources, tt.wantOutputs)
+			}
+		})
+	}
+}
+
+func TestStateValidatorRejectsInvalidState(t *testing.T) {
+	validator := newStateValidator(bytes.NewReader([]byte("<html></html>")), 3, nil)
+	defer validator.Close()
+
+	if _, err := io.ReadAll(validator); err == nil {
+		t.Fatal("esperado erro de validação")
+	}
+}
+
+func TestStateValidatorReadAfterEOF(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "estado válido", input: validState},
+		{name: "estado inválido", input: "<html></html>", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			validator := newStateValidator(bytes.NewReader([]byte(tt.input)), 3, func(stateIdentity) { calls++ })
+			defer validator.Close()
+
+			_, first := io.ReadAll(validator)
+
+			// Uma nova leitura após o fim do stream repete o resultado sem bloquear
+			for i := 0; i < 2; i++ {
+				n, err := validator.Read(make([]byte, 16))
+				if n != 0 {
+					t.Fatalf("leitura após o EOF retornou %d bytes", n)
+				}
+				if tt.wantErr && (err == nil || err != first) {
+					t.Fatalf("erro = %v, esperado %v", err, first)
+				}
+				if !tt.wantErr && err != io.EOF {
+					t.Fatalf("erro = %v, esperado io.EOF", err)
+				}
+			}
+
+			if !tt.wantErr && calls != 1 {
+				t.Fatalf("onValid chamado %d vezes, esperado 1", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package migrator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"terraform-cloud-s3-migrator/internal/s3client"
+
+	"github.com/sirupsen/logrus"
+)
+
+// VerifyStatus representa o resultado da verificação de um workspace
+type VerifyStatus string
+
+const (
+	VerifyPass        VerifyStatus = "PASS"
+	VerifyFail        VerifyStatus = "FAIL"
+	VerifyNotMigrated VerifyStatus = "NOT MIGRATED"
+)
+
+type VerifyResult struct {
+	WorkspaceName string
+	S3Name        string
+	Status        VerifyStatus
+	SourceHash    string
+	TargetHash    string
+	Error         string
+	Diff          *StateDiff // Preenchido em divergências quando MigrationOptions.Diff está ativo
+}
+
+// Verify compara o estado atual do Terraform Cloud com o estado enviado ao S3
+func (m *Migrator) Verify(ctx context.Context, options MigrationOptions) ([]VerifyResult, error) {
+	if err := m.ValidateConnections(ctx); err != nil {
+		return nil, err
+	}
+
+	workspaces, err := m.selectWorkspaces(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	mf, err := m.loadManifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []VerifyResult
+
+	for _, ws := range workspaces {
+		if !ws.HasState {
+			m.logger.WithField("workspace", ws.Name).Debug("Workspace sem estado do Terraform, pulando")
+			continue
+		}
+
+		// O manifesto prevalece sobre as regras atuais de nome, que podem ter mudado desde a migração
+		s3Name := m.s3Name(ws.Name)
+		if entry, ok := mf.lookup(ws.Name); ok {
+			s3Name = entry.S3Name
+		}
+		result := VerifyResult{
+			WorkspaceName: ws.Name,
+			S3Name:        s3Name,
+		}
+		logger := m.logger.WithField("workspace", ws.Name)
+
+		stateData, err := m.tfClient.GetWorkspaceState(ctx, ws.ID)
+		if err != nil {
+			result.Status = VerifyFail
+			result.Error = err.Error()
+			logger.WithError(err).Error("Erro ao obter estado do Terraform Cloud")
+			results = append(results, result)
+			continue
+		}
+		result.SourceHash = hashContent(stateData.StateContent)
+
+		targetContent, err := m.readMigratedState(ctx, ws.Name, s3Name)
+		if err != nil {
+			if errors.Is(err, s3client.ErrStateNotFound) {
+				result.Status = VerifyNotMigrated
+				logger.Warn("Estado ainda não migrado para o S3")
+			} else {
+				result.Status = VerifyFail
+				result.Error = err.Error()
+				logger.WithError(err).Error("Erro ao obter estado do S3")
+			}
+			results = append(results, result)
+			continue
+		}
+		result.TargetHash = hashContent(targetContent)
+
+		// Estados enviados com --rewrite-backend são comparados com o mesmo bloco removido da origem
+		if result.SourceHash != result.TargetHash && m.backendRewritten(ctx, ws.Name, s3Name) {
+			if stripped, err := stripRemoteBackend(stateData.StateContent); err == nil {
+				result.SourceHash = hashContent(stripped)
+			}
+		}
+
+		if result.SourceHash == result.TargetHash {
+			result.Status = VerifyPass
+			logger.Debug("Estado verificado com sucesso")
+		} else {
+			result.Status = VerifyFail
+			result.Error = "hash SHA-256 divergente"
+			logger.WithFields(logrus.Fields{
+				"source_hash": result.SourceHash,
+				"target_hash": result.TargetHash,
+			}).Error("Estado no S3 diverge do Terraform Cloud")
+
+			if options.Diff {
+				diff, err := diffStates(stateData.StateContent, targetContent)
+				if err != nil {
+					logger.WithError(err).Warn("Não foi possível comparar os recursos dos estados")
+				}
+				result.Diff = diff
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// backendRewritten indica se o metadata.json registra a remoção do bloco backend na migração
+func (m *Migrator) backendRewritten(ctx context.Context, workspaceName, s3Name string) bool {
+	reader, ok := m.destination(workspaceName).(stateReader)
+	if !ok {
+		return false
+	}
+
+	metadata, err := reader.GetStateMetadata(ctx, m.config.TerraformCloud.Organization, s3Name)
+	if err != nil {
+		return false
+	}
+
+	rewritten, _ := metadata["backend_rewritten"].(bool)
+	return rewritten
+}
+
+// readMigratedState baixa o estado migrado do destino do workspace
+func (m *Migrator) readMigratedState(ctx context.Context, workspaceName, s3Name string) ([]byte, error) {
+	dest := m.destination(workspaceName)
+	reader, ok := dest.(stateReader)
+	if !ok {
+		return nil, unsupportedError(dest, "a leitura do estado migrado")
+	}
+	return reader.DownloadState(ctx, m.config.TerraformCloud.Organization, s3Name)
+}
+
+// hashContent calcula o hash SHA-256 em hexadecimal do conteúdo
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
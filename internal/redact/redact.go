@@ -0,0 +1,45 @@
+package redact
+
+import "regexp"
+
+// Placeholder usado no lugar dos valores removidos
+const Placeholder = "REDACTED"
+
+var (
+	// Parâmetros de query com credenciais em URLs pré-assinadas (S3 SigV4, archivist do Terraform Cloud)
+	queryPattern = regexp.MustCompile(`(?i)([?&](?:x-amz-[a-z-]+|signature|sig|token|access_token|expires|awsaccesskeyid|key-id)=)[^&\s"']+`)
+
+	// O archivist do Terraform Cloud embute a credencial no caminho da URL de download
+	archivistPattern = regexp.MustCompile(`(/v1/object/)[^\s"'?]+`)
+
+	// Tokens enviados no header Authorization
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+)
+
+// String remove credenciais de URLs pré-assinadas e tokens Bearer do texto
+func String(text string) string {
+	text = queryPattern.ReplaceAllString(text, "${1}"+Placeholder)
+	text = archivistPattern.ReplaceAllString(text, "${1}"+Placeholder)
+	return bearerPattern.ReplaceAllString(text, "${1}"+Placeholder)
+}
+
+// redactedError exibe a mensagem do erro original sem segredos, preservando a cadeia para errors.Is e errors.As
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return String(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// Error envolve o erro para que sua mensagem seja exibida sem segredos. Retorna nil se err for nil.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{err: err}
+}
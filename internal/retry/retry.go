@@ -0,0 +1,138 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Backoff define a política de retentativas com crescimento exponencial e jitter
+type Backoff struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// retryAfterer é implementado por erros que indicam quanto tempo aguardar (ex: header Retry-After)
+type retryAfterer interface {
+	RetryAfter() time.Duration
+}
+
+// transient é implementado por erros que se declaram transitórios (ex: checksum divergente após upload)
+type transient interface {
+	Transient() bool
+}
+
+// statusCoder é implementado por erros que carregam o status HTTP da resposta
+// (ex: erros de resposta do AWS SDK e terraform.HTTPError)
+type statusCoder interface {
+	HTTPStatusCode() int
+}
+
+// Delay calcula o tempo de espera antes da próxima tentativa.
+// O atraso dobra a cada tentativa, limitado a MaxDelay, e metade dele é aleatória
+// para evitar que vários workers retentem ao mesmo tempo.
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.BaseDelay
+	for i := 1; i < attempt && delay < b.MaxDelay; i++ {
+		delay *= 2
+	}
+	if b.MaxDelay > 0 && delay > b.MaxDelay {
+		delay = b.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// Do executa fn até que tenha sucesso, retorne um erro não transitório ou as tentativas se esgotem.
+// onRetry, se informado, é chamado antes de cada espera.
+func Do(ctx context.Context, b Backoff, fn func() error, onRetry func(attempt int, delay time.Duration, err error)) error {
+	attempts := b.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = fn()
+		if err == nil || !IsRetryable(err) || attempt == attempts {
+			return err
+		}
+
+		delay := b.Delay(attempt)
+
+		// Respeitar o tempo de espera solicitado pelo servidor quando maior que o backoff
+		var ra retryAfterer
+		if errors.As(err, &ra) && ra.RetryAfter() > delay {
+			delay = ra.RetryAfter()
+		}
+
+		if onRetry != nil {
+			onRetry(attempt, delay, err)
+		}
+
+		if err := Sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// Sleep aguarda o tempo informado ou até o contexto ser cancelado
+func Sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// IsRetryable indica se o erro é transitório e vale uma nova tentativa:
+// HTTP 429, HTTP 5xx e falhas de rede. Erros de autenticação e demais erros HTTP 4xx não são retentados.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || IsAuthError(err) {
+		return false
+	}
+
+	var t transient
+	if errors.As(err, &t) {
+		return t.Transient()
+	}
+
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		code := sc.HTTPStatusCode()
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsAuthError indica se o erro é uma falha de autenticação ou permissão (HTTP 401 ou 403),
+// que se repete em todas as tentativas e em todos os workspaces
+func IsAuthError(err error) bool {
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		code := sc.HTTPStatusCode()
+		return code == http.StatusUnauthorized || code == http.StatusForbidden
+	}
+	return false
+}
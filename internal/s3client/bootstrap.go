@@ -0,0 +1,177 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// bucketCreationTimeout é o tempo máximo de espera até o bucket recém-criado ficar disponível
+const bucketCreationTimeout = 2 * time.Minute
+
+// EnsureBucket cria o bucket na região configurada com versionamento, bloqueio de acesso público
+// e criptografia padrão. Um bucket existente não é alterado, mas precisa pertencer à conta configurada.
+// Retorna true se o bucket foi criado.
+func (c *Client) EnsureBucket(ctx context.Context) (bool, error) {
+	if err := c.checkCredentials(ctx); err != nil {
+		return false, err
+	}
+
+	_, err := c.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(c.bucket),
+	})
+	switch {
+	case err == nil:
+		if err := c.checkBucketOwner(ctx); err != nil {
+			return false, err
+		}
+		c.logger.WithField("bucket", c.bucket).Info("Bucket S3 já existe, nenhuma alteração realizada")
+		return false, nil
+	case isHTTPStatus(err, http.StatusNotFound):
+		// Bucket não existe, seguir com a criação
+	case isHTTPStatus(err, http.StatusForbidden):
+		return false, fmt.Errorf("bucket S3 '%s' já existe e pertence a outra conta (ou as credenciais não têm acesso a ele)", c.bucket)
+	default:
+		return false, fmt.Errorf("erro ao verificar o bucket S3 '%s': %w", c.bucket, err)
+	}
+
+	input := &s3.CreateBucketInput{
+		Bucket:          aws.String(c.bucket),
+		ObjectOwnership: types.ObjectOwnershipBucketOwnerEnforced,
+	}
+	// us-east-1 não aceita LocationConstraint
+	if c.region != "us-east-1" {
+		input.CreateBucketConfiguration = &types.CreateBucketConfiguration{
+			LocationConstraint: types.BucketLocationConstraint(c.region),
+		}
+	}
+
+	if _, err := c.s3Client.CreateBucket(ctx, input); err != nil {
+		var alreadyExists *types.BucketAlreadyExists
+		if errors.As(err, &alreadyExists) {
+			return false, fmt.Errorf("bucket S3 '%s' já existe e pertence a outra conta", c.bucket)
+		}
+		return false, fmt.Errorf("erro ao criar o bucket S3 '%s': %w", c.bucket, err)
+	}
+
+	waiter := s3.NewBucketExistsWaiter(c.s3Client)
+	if err := waiter.Wait(ctx, &s3.HeadBucketInput{Bucket: aws.String(c.bucket)}, bucketCreationTimeout); err != nil {
+		return true, fmt.Errorf("erro ao aguardar a criação do bucket S3 '%s': %w", c.bucket, err)
+	}
+
+	// As credenciais podem pertencer a uma conta diferente de aws.accountid
+	if err := c.checkBucketOwner(ctx); err != nil {
+		return true, fmt.Errorf("bucket criado com credenciais de outra conta, verifique aws.profile e aws.role_arn: %w", err)
+	}
+
+	if err := c.configureBucket(ctx); err != nil {
+		return true, err
+	}
+
+	c.logger.WithField("bucket", c.bucket).Info("Bucket S3 criado com sucesso")
+	return true, nil
+}
+
+// configureBucket aplica as proteções do bucket: bloqueio de acesso público, versionamento e criptografia padrão
+func (c *Client) configureBucket(ctx context.Context) error {
+	_, err := c.s3Client.PutPublicAccessBlock(ctx, &s3.PutPublicAccessBlockInput{
+		Bucket: aws.String(c.bucket),
+		PublicAccessBlockConfiguration: &types.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(true),
+			BlockPublicPolicy:     aws.Bool(true),
+			IgnorePublicAcls:      aws.Bool(true),
+			RestrictPublicBuckets: aws.Bool(true),
+		},
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao bloquear acesso público do bucket S3 '%s': %w", c.bucket, err)
+	}
+
+	_, err = c.s3Client.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+		Bucket: aws.String(c.bucket),
+		VersioningConfiguration: &types.VersioningConfiguration{
+			Status: types.BucketVersioningStatusEnabled,
+		},
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao habilitar versionamento do bucket S3 '%s': %w", c.bucket, err)
+	}
+
+	encryption := types.ServerSideEncryptionByDefault{
+		SSEAlgorithm: types.ServerSideEncryptionAes256,
+	}
+	if c.kmsKeyID != "" {
+		encryption = types.ServerSideEncryptionByDefault{
+			SSEAlgorithm:   types.ServerSideEncryptionAwsKms,
+			KMSMasterKeyID: aws.String(c.kmsKeyID),
+		}
+	}
+
+	_, err = c.s3Client.PutBucketEncryption(ctx, &s3.PutBucketEncryptionInput{
+		Bucket: aws.String(c.bucket),
+		ServerSideEncryptionConfiguration: &types.ServerSideEncryptionConfiguration{
+			Rules: []types.ServerSideEncryptionRule{
+				{
+					ApplyServerSideEncryptionByDefault: &encryption,
+					BucketKeyEnabled:                   aws.Bool(c.kmsKeyID != ""),
+				},
+			},
+		},
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao configurar criptografia padrão do bucket S3 '%s': %w", c.bucket, err)
+	}
+
+	return nil
+}
+
+// VersioningEnabled indica se o versionamento do bucket está habilitado e retorna o status atual
+// ("Disabled" se nunca foi habilitado)
+func (c *Client) VersioningEnabled(ctx context.Context) (bool, string, error) {
+	output, err := c.s3Client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket:              aws.String(c.bucket),
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+	if err != nil {
+		return false, "", fmt.Errorf("erro ao verificar versionamento do bucket S3 '%s': %w", c.bucket, err)
+	}
+
+	if output.Status == "" {
+		return false, "Disabled", nil
+	}
+
+	return output.Status == types.BucketVersioningStatusEnabled, string(output.Status), nil
+}
+
+// checkBucketOwner verifica se o bucket pertence à conta configurada em aws.accountid
+func (c *Client) checkBucketOwner(ctx context.Context) error {
+	if c.accountID == "" {
+		return nil
+	}
+
+	_, err := c.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket:              aws.String(c.bucket),
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("bucket S3 '%s' não pertence à conta %s: %w", c.bucket, c.accountID, err)
+	}
+
+	return nil
+}
+
+// isHTTPStatus indica se o erro é uma resposta HTTP do S3 com o status informado
+func isHTTPStatus(err error, status int) bool {
+	var responseErr *awshttp.ResponseError
+	return errors.As(err, &responseErr) && responseErr.HTTPStatusCode() == status
+}
@@ -0,0 +1,89 @@
+package s3client
+
+import (
+	"container/list"
+	"sync"
+)
+
+// objectCacheSize limita as entradas mantidas em memória; cada workspace usa até duas
+const objectCacheSize = 1024
+
+// cacheEntry guarda o último resultado visto para uma chave: a existência do estado
+// ou o conteúdo do metadata.json
+type cacheEntry struct {
+	key      string
+	exists   bool
+	metadata map[string]interface{}
+}
+
+// objectCache é um LRU limitado, válido apenas durante a execução do processo, que evita
+// repetir HeadObject e GetObject do metadata.json em reconcile e --overwrite.
+// Uploads e remoções invalidam as chaves afetadas.
+type objectCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newObjectCache(size int) *objectCache {
+	return &objectCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// get retorna a entrada da chave, marcando-a como usada recentemente
+func (c *objectCache) get(key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(cacheEntry), true
+}
+
+// put grava a entrada, descartando a menos usada quando o limite é atingido
+func (c *objectCache) put(entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[entry.key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[entry.key] = c.order.PushFront(entry)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(cacheEntry).key)
+	}
+}
+
+// remove invalida as chaves informadas
+func (c *objectCache) remove(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.order.Remove(element)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// copyMetadata evita que o chamador altere o mapa mantido no cache
+func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
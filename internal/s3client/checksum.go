@@ -0,0 +1,118 @@
+package s3client
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/aws/smithy-go"
+)
+
+// ChecksumMismatchError indica que o checksum calculado pelo S3 difere do conteúdo enviado
+type ChecksumMismatchError struct {
+	Algorithm string // SHA-256 ou MD5 (ETag)
+	Key       string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum %s divergente para %s: esperado %s, recebido %s", e.Algorithm, e.Key, e.Expected, e.Actual)
+}
+
+// Transient indica que a falha é transitória e o upload deve ser retentado
+func (e *ChecksumMismatchError) Transient() bool {
+	return true
+}
+
+// badDigestError indica que o S3 recusou o upload porque o conteúdo recebido não confere
+// com o Content-MD5 enviado (BadDigest), o que aponta corrupção no transporte
+type badDigestError struct {
+	err error
+}
+
+func (e *badDigestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *badDigestError) Unwrap() error {
+	return e.err
+}
+
+// Transient indica que a falha é transitória e o upload deve ser retentado
+func (e *badDigestError) Transient() bool {
+	return true
+}
+
+// markBadDigest marca como transitório o erro BadDigest, que de outra forma seria tratado
+// como um erro 400 definitivo
+func markBadDigest(err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "BadDigest" {
+		return &badDigestError{err: err}
+	}
+	return err
+}
+
+// checksumWriter calcula o checksum SHA-256 no mesmo formato retornado pelo S3:
+// o hash do objeto inteiro em uploads simples, ou o hash dos hashes de cada parte
+// seguido de "-N" em multipart uploads
+type checksumWriter struct {
+	partSize  int64
+	part      hash.Hash
+	partBytes int64
+	total     int64
+	digests   []byte
+	parts     int
+}
+
+func newChecksumWriter(partSize int64) *checksumWriter {
+	return &checksumWriter{
+		partSize: partSize,
+		part:     sha256.New(),
+	}
+}
+
+func (w *checksumWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		n := int64(len(p))
+		if remaining := w.partSize - w.partBytes; n > remaining {
+			n = remaining
+		}
+
+		w.part.Write(p[:n])
+		w.partBytes += n
+		w.total += n
+		p = p[n:]
+
+		if w.partBytes == w.partSize {
+			w.finishPart()
+		}
+	}
+	return written, nil
+}
+
+// finishPart encerra a parte atual e guarda o seu hash
+func (w *checksumWriter) finishPart() {
+	w.digests = w.part.Sum(w.digests)
+	w.parts++
+	w.part.Reset()
+	w.partBytes = 0
+}
+
+// Sum retorna o checksum em base64 no formato do S3.
+// O transfer manager usa multipart quando o conteúdo preenche ao menos uma parte.
+func (w *checksumWriter) Sum() string {
+	if w.total < w.partSize {
+		return base64.StdEncoding.EncodeToString(w.part.Sum(nil))
+	}
+
+	if w.partBytes > 0 {
+		w.finishPart()
+	}
+	composite := sha256.Sum256(w.digests)
+	return fmt.Sprintf("%s-%d", base64.StdEncoding.EncodeToString(composite[:]), w.parts)
+}
@@ -2,49 +2,141 @@ package s3client
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"path/filepath"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"terraform-cloud-s3-migrator/internal/redact"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	variablesFilename   = "variables.json"
+	compressedExtension = ".gz"
+	historyDir          = "history"
 )
 
+// ErrStateNotFound indica que o estado ainda não existe no S3
+var ErrStateNotFound = errors.New("estado não encontrado no S3")
+
 type Client struct {
-	s3Client  *s3.Client
-	bucket    string
-	prefix    string
-	accountID string
-	logger    *logrus.Entry
+	KeyLayout
+
+	s3Client     *s3.Client
+	uploader     *manager.Uploader
+	bucket       string
+	region       string
+	kmsKeyID     string
+	compress     bool
+	objectTags   map[string]string
+	extraMeta    map[string]string
+	storageClass types.StorageClass
+	bandwidth    *rate.Limiter
+	logger       *logrus.Entry
+	cache        *objectCache
+
+	// Credenciais resolvidas pelo SDK e perfil usado, para diagnosticar sessões SSO expiradas
+	credentials aws.CredentialsProvider
+	profile     string
+
+	// Tabela DynamoDB de lock usada pelo backend S3 do Terraform (opcional)
+	dynamoClient *dynamodb.Client
+	lockTable    string
+}
+
+// Options agrupa as configurações usadas para criar o client S3
+type Options struct {
+	Region    string
+	Bucket    string
+	Prefix    string
+	Profile   string
+	AccountID string
+	KMSKeyID  string
+	Compress  bool // Comprime o estado com gzip antes do upload
+
+	// Template da chave dos objetos (ver generateStateKey); vazio usa DefaultKeyTemplate
+	KeyTemplate string
+
+	// Nomes dos arquivos de estado e de metadados, com o placeholder {workspace} opcional;
+	// vazio usa DefaultStateFilename e DefaultMetadataFilename
+	StateFilename    string
+	MetadataFilename string
+
+	DynamoDBTable string
+
+	// Tamanho das partes em MB e concorrência do multipart upload (zero usa os padrões do SDK)
+	UploadPartSizeMB  int64
+	UploadConcurrency int
+
+	// Tags aplicadas aos objetos enviados, além das tags automáticas
+	ObjectTags map[string]string
+
+	// Metadados adicionais gravados no estado e nos metadados; não sobrescrevem as chaves do migrator
+	ExtraMetadata map[string]string
+
+	// Classe de armazenamento dos objetos enviados (vazio usa STANDARD)
+	StorageClass string
+
+	// Limite de banda dos uploads, compartilhado entre clients (nil não limita; ver NewBandwidthLimiter)
+	Bandwidth *rate.Limiter
+
+	// Endpoint S3 compatível (ex: MinIO ou LocalStack)
+	EndpointURL string
+
+	// Role assumida para acessar o bucket em outra conta (opcional)
+	RoleARN    string
+	ExternalID string
 }
 
 type UploadOptions struct {
-	Key         string
-	Content     []byte
-	ContentType string
-	Metadata    map[string]string
+	Key                  string
+	Body                 io.Reader
+	ContentType          string
+	ContentEncoding      string
+	ContentMD5           string // MD5 do conteúdo em base64, conferido pelo S3 no recebimento
+	Metadata             map[string]string
+	ServerSideEncryption types.ServerSideEncryption
+	SSEKMSKeyID          string
+	Tagging              string
 }
 
 // NewClient cria um novo client S3
-func NewClient(region, bucket, prefix, profile, accountID string) (*Client, error) {
+func NewClient(options Options) (*Client, error) {
 	var cfg aws.Config
 	var err error
 	
-	if profile != "" {
+	// Perfis com sso_session (aws sso login) são resolvidos pelo SDK com o provider de token SSO,
+	// que renova o token em cache automaticamente enquanto a sessão for válida
+	if options.Profile != "" {
 		// Carregar configuração com perfil específico
 		cfg, err = config.LoadDefaultConfig(context.TODO(),
-			config.WithRegion(region),
-			config.WithSharedConfigProfile(profile),
+			config.WithRegion(options.Region),
+			config.WithSharedConfigProfile(options.Profile),
 		)
 	} else {
 		// Carregar configuração padrão
 		cfg, err = config.LoadDefaultConfig(context.TODO(),
-			config.WithRegion(region),
+			config.WithRegion(options.Region),
 		)
 	}
 	
@@ -52,124 +144,697 @@ func NewClient(region, bucket, prefix, profile, accountID string) (*Client, erro
 		return nil, fmt.Errorf("erro ao carregar configuração AWS: %w", err)
 	}
 
-	s3Client := s3.NewFromConfig(cfg)
+	// Assumir a role a partir das credenciais base (perfil ou cadeia padrão)
+	if options.RoleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), options.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "terraform-cloud-s3-migrator"
+			if options.ExternalID != "" {
+				o.ExternalID = aws.String(options.ExternalID)
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	s3Client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		// Serviços compatíveis com S3 normalmente não suportam virtual-hosted-style
+		if options.EndpointURL != "" {
+			o.BaseEndpoint = aws.String(options.EndpointURL)
+			o.UsePathStyle = true
+		}
+	})
+
+	// Transfer manager para multipart upload de estados grandes
+	uploader := manager.NewUploader(s3Client, func(u *manager.Uploader) {
+		if options.UploadPartSizeMB > 0 {
+			u.PartSize = options.UploadPartSizeMB * 1024 * 1024
+		}
+		if options.UploadConcurrency > 0 {
+			u.Concurrency = options.UploadConcurrency
+		}
+	})
 
 	logger := logrus.WithFields(logrus.Fields{
 		"component": "s3-client",
-		"bucket":    bucket,
-		"region":    region,
+		"bucket":    options.Bucket,
+		"region":    options.Region,
 	})
 
 	client := &Client{
-		s3Client:  s3Client,
-		bucket:    bucket,
-		prefix:    prefix,
-		accountID: accountID,
-		logger:    logger,
+		s3Client:     s3Client,
+		uploader:     uploader,
+		KeyLayout:    NewKeyLayout(options),
+		bucket:       options.Bucket,
+		region:       options.Region,
+		kmsKeyID:     options.KMSKeyID,
+		compress:     options.Compress,
+		objectTags:   options.ObjectTags,
+		extraMeta:    options.ExtraMetadata,
+		storageClass: types.StorageClass(options.StorageClass),
+		bandwidth:    options.Bandwidth,
+		credentials:  cfg.Credentials,
+		profile:      options.Profile,
+		logger:       logger,
+		lockTable:    options.DynamoDBTable,
+		cache:        newObjectCache(objectCacheSize),
+	}
+
+	if options.DynamoDBTable != "" {
+		client.dynamoClient = dynamodb.NewFromConfig(cfg)
 	}
 
 	return client, nil
 }
 
+// Bucket retorna o bucket de destino do client
+func (c *Client) Bucket() string {
+	return c.bucket
+}
+
 // ValidateConnection valida se a conexão com S3 está funcionando
 func (c *Client) ValidateConnection(ctx context.Context) error {
 	c.logger.Debug("Validando conexão com S3")
 
+	if err := c.checkCredentials(ctx); err != nil {
+		return err
+	}
+
 	_, err := c.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
-		Bucket: aws.String(c.bucket),
+		Bucket:              aws.String(c.bucket),
+		ExpectedBucketOwner: c.expectedBucketOwner(),
 	})
 	if err != nil {
-		return fmt.Errorf("erro ao validar acesso ao bucket S3 '%s': %w", c.bucket, err)
+		if c.accountID != "" {
+			return fmt.Errorf("erro ao validar acesso ao bucket S3 '%s' (conta esperada: %s): %w", c.bucket, c.accountID, redact.Error(err))
+		}
+		return fmt.Errorf("erro ao validar acesso ao bucket S3 '%s': %w", c.bucket, redact.Error(err))
+	}
+
+	if err := c.validateRegion(ctx); err != nil {
+		return err
 	}
 
 	c.logger.Info("Conexão com S3 validada com sucesso")
 	return nil
 }
 
-// UploadState faz upload de um arquivo de estado para S3
-func (c *Client) UploadState(ctx context.Context, organization, workspaceName string, stateContent []byte, metadata map[string]interface{}) error {
+// validateRegion compara a região real do bucket com a região configurada.
+// Sem essa verificação o S3 responde com redirecionamentos 301 e os uploads falham de forma confusa.
+func (c *Client) validateRegion(ctx context.Context) error {
+	output, err := c.s3Client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+		Bucket:              aws.String(c.bucket),
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao obter a região do bucket S3 '%s': %w", c.bucket, err)
+	}
+
+	// LocationConstraint vazio significa us-east-1 e "EU" é o valor legado de eu-west-1
+	actualRegion := string(output.LocationConstraint)
+	switch actualRegion {
+	case "":
+		actualRegion = "us-east-1"
+	case "EU":
+		actualRegion = "eu-west-1"
+	}
+
+	if actualRegion != c.region {
+		return fmt.Errorf("bucket S3 '%s' está na região %s, mas a região configurada é %s (ajuste aws.region)", c.bucket, actualRegion, c.region)
+	}
+
+	return nil
+}
+
+// UploadState faz upload de um arquivo de estado para S3 a partir de um stream, sem carregá-lo em memória
+func (c *Client) UploadState(ctx context.Context, organization, workspaceName string, body io.Reader, metadata map[string]interface{}) error {
 	// Gerar chave do objeto S3
-	stateKey := c.generateStateKey(organization, workspaceName, "terraform.tfstate")
-	metadataKey := c.generateStateKey(organization, workspaceName, "metadata.json")
+	stateKey := c.stateKeys(organization, workspaceName)[0]
+	metadataKey := c.generateStateKey(organization, workspaceName, c.metadataFilename(workspaceName))
 
 	c.logger.WithFields(logrus.Fields{
-		"workspace":   workspaceName,
-		"state_key":   stateKey,
-		"size_bytes":  len(stateContent),
+		"workspace": workspaceName,
+		"state_key": stateKey,
 	}).Info("Fazendo upload do estado")
 
-	// Upload do arquivo de estado
-	err := c.uploadFile(ctx, UploadOptions{
-		Key:         stateKey,
-		Content:     stateContent,
+	expected, err := c.uploadStateObject(ctx, organization, workspaceName, stateKey, body)
+	c.cache.remove(c.baseStateKey(organization, workspaceName))
+	if err != nil {
+		return err
+	}
+
+	// Registrar nos metadados a compressão e o checksum, sem alterar o mapa do chamador
+	objectMetadata := make(map[string]interface{}, len(metadata)+2)
+	for key, value := range metadata {
+		objectMetadata[key] = value
+	}
+	objectMetadata["compressed"] = c.compress
+	objectMetadata["checksum_sha256"] = expected
+	objectMetadata["has_state"] = true
+
+	if err := c.uploadMetadata(ctx, organization, workspaceName, metadataKey, objectMetadata); err != nil {
+		return err
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"workspace":     workspaceName,
+		"state_key":     stateKey,
+		"metadata_key":  metadataKey,
+	}).Info("Upload concluído com sucesso")
+
+	return nil
+}
+
+// UploadMetadata grava apenas o metadata.json do workspace, sem objeto de estado.
+// Usado para registrar workspaces sem estado no inventário do bucket.
+func (c *Client) UploadMetadata(ctx context.Context, organization, workspaceName string, metadata map[string]interface{}) error {
+	metadataKey := c.generateStateKey(organization, workspaceName, c.metadataFilename(workspaceName))
+
+	objectMetadata := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		objectMetadata[key] = value
+	}
+
+	if err := c.uploadMetadata(ctx, organization, workspaceName, metadataKey, objectMetadata); err != nil {
+		return err
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"workspace":    workspaceName,
+		"metadata_key": metadataKey,
+	}).Info("Metadados do workspace gravados")
+
+	return nil
+}
+
+// uploadMetadata acrescenta migration.extra_metadata, sem sobrescrever as chaves existentes,
+// e grava o metadata.json
+func (c *Client) uploadMetadata(ctx context.Context, organization, workspaceName, metadataKey string, objectMetadata map[string]interface{}) error {
+	for key, value := range c.extraMeta {
+		if _, ok := objectMetadata[key]; !ok {
+			objectMetadata[key] = value
+		}
+	}
+
+	metadataJSON, err := json.MarshalIndent(objectMetadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar metadados para workspace %s: %w", workspaceName, err)
+	}
+
+	defer c.cache.remove(metadataKey)
+
+	_, err = c.uploadFile(ctx, c.withEncryption(UploadOptions{
+		Key:         metadataKey,
+		Body:        bytes.NewReader(metadataJSON),
+		ContentType: "application/json",
+		Metadata: c.objectMetadata(map[string]string{
+			"workspace":    workspaceName,
+			"organization": organization,
+			"file-type":    "metadata",
+		}),
+		Tagging: c.objectTagging(organization),
+	}))
+	if err != nil {
+		return fmt.Errorf("erro ao fazer upload dos metadados do workspace %s: %w", workspaceName, redact.Error(err))
+	}
+
+	return nil
+}
+
+// UploadVariables grava o variables.json do workspace ao lado do estado
+func (c *Client) UploadVariables(ctx context.Context, organization, workspaceName string, content []byte) error {
+	_, err := c.uploadFile(ctx, c.withEncryption(UploadOptions{
+		Key:         c.generateStateKey(organization, workspaceName, variablesFilename),
+		Body:        bytes.NewReader(content),
 		ContentType: "application/json",
 		Metadata: map[string]string{
 			"workspace":    workspaceName,
 			"organization": organization,
-			"file-type":    "terraform-state",
+			"file-type":    "variables",
 		},
-	})
+		Tagging: c.objectTagging(organization),
+	}))
 	if err != nil {
-		return fmt.Errorf("erro ao fazer upload do estado do workspace %s: %w", workspaceName, err)
+		return fmt.Errorf("erro ao fazer upload das variáveis do workspace %s: %w", workspaceName, redact.Error(err))
 	}
 
-	// Preparar e fazer upload dos metadados
-	metadataJSON, err := json.MarshalIndent(metadata, "", "  ")
-	if err != nil {
-		return fmt.Errorf("erro ao serializar metadados para workspace %s: %w", workspaceName, err)
+	return nil
+}
+
+// UploadStateVersion faz upload de uma versão anterior do estado para a pasta de histórico do workspace
+func (c *Client) UploadStateVersion(ctx context.Context, organization, workspaceName string, serial int64, body io.Reader) error {
+	historyKey := c.generateStateKey(organization, workspaceName, fmt.Sprintf("%s/%d-%s", historyDir, serial, c.stateFilename(workspaceName)))
+	if c.compress {
+		historyKey += compressedExtension
 	}
 
-	err = c.uploadFile(ctx, UploadOptions{
-		Key:         metadataKey,
-		Content:     metadataJSON,
+	c.logger.WithFields(logrus.Fields{
+		"workspace":   workspaceName,
+		"serial":      serial,
+		"history_key": historyKey,
+	}).Debug("Fazendo upload de versão do histórico")
+
+	_, err := c.uploadStateObject(ctx, organization, workspaceName, historyKey, body)
+	return err
+}
+
+// uploadStateObject envia o conteúdo de um estado para a chave informada, aplicando compressão,
+// criptografia e tags, e confere o checksum retornado pelo S3
+func (c *Client) uploadStateObject(ctx context.Context, organization, workspaceName, key string, body io.Reader) (string, error) {
+	stateOptions := UploadOptions{
+		Key:         key,
+		Body:        body,
 		ContentType: "application/json",
-		Metadata: map[string]string{
+		Metadata: c.objectMetadata(map[string]string{
 			"workspace":    workspaceName,
 			"organization": organization,
-			"file-type":    "metadata",
+			"file-type":    "terraform-state",
+		}),
+		Tagging: c.objectTagging(organization),
+	}
+
+	if c.compress {
+		compressed := compressStream(body)
+		defer compressed.Close()
+		stateOptions.Body = compressed
+		stateOptions.ContentEncoding = "gzip"
+	}
+
+	// Estados menores que uma parte são enviados em um único PutObject: o conteúdo é lido em memória
+	// para enviar o Content-MD5 e conferir o ETag. Em multipart uploads o ETag não é o MD5 do objeto,
+	// e a integridade é conferida apenas pelo checksum SHA-256.
+	head, err := io.ReadAll(io.LimitReader(stateOptions.Body, c.uploader.PartSize))
+	if err != nil {
+		return "", fmt.Errorf("erro ao fazer upload do estado do workspace %s: %w", workspaceName, err)
+	}
+
+	var contentMD5 []byte
+	if int64(len(head)) < c.uploader.PartSize {
+		sum := md5.Sum(head)
+		contentMD5 = sum[:]
+		stateOptions.ContentMD5 = base64.StdEncoding.EncodeToString(contentMD5)
+		stateOptions.Body = bytes.NewReader(head)
+	} else {
+		stateOptions.Body = io.MultiReader(bytes.NewReader(head), stateOptions.Body)
+	}
+
+	// Calcular o checksum dos bytes efetivamente enviados para comparar com o retornado pelo S3
+	checksum := newChecksumWriter(c.uploader.PartSize)
+	stateOptions.Body = io.TeeReader(stateOptions.Body, checksum)
+
+	output, err := c.uploadFile(ctx, c.withEncryption(stateOptions))
+	if err != nil {
+		return "", fmt.Errorf("erro ao fazer upload do estado do workspace %s: %w", workspaceName, markBadDigest(err))
+	}
+
+	expected := checksum.Sum()
+	if actual := aws.ToString(output.ChecksumSHA256); actual == "" {
+		c.logger.WithField("state_key", key).Debug("S3 não retornou checksum SHA-256, verificação ignorada")
+	} else if actual != expected {
+		return "", fmt.Errorf("erro ao fazer upload do estado do workspace %s: %w", workspaceName, &ChecksumMismatchError{
+			Algorithm: "SHA-256",
+			Key:       key,
+			Expected:  expected,
+			Actual:    actual,
+		})
+	}
+
+	if contentMD5 != nil {
+		if err := c.verifyETag(key, output.ETag, contentMD5); err != nil {
+			return "", fmt.Errorf("erro ao fazer upload do estado do workspace %s: %w", workspaceName, err)
+		}
+	}
+
+	return expected, nil
+}
+
+// verifyETag confere se o ETag de um upload simples é o MD5 do conteúdo enviado.
+// Com SSE-KMS o ETag não corresponde ao MD5, e a verificação é ignorada.
+func (c *Client) verifyETag(key string, etag *string, contentMD5 []byte) error {
+	actual := strings.Trim(aws.ToString(etag), `"`)
+	if c.kmsKeyID != "" || actual == "" {
+		c.logger.WithField("state_key", key).Debug("ETag não corresponde ao MD5 do objeto, verificação ignorada")
+		return nil
+	}
+
+	if expected := hex.EncodeToString(contentMD5); !strings.EqualFold(actual, expected) {
+		return &ChecksumMismatchError{
+			Algorithm: "MD5 (ETag)",
+			Key:       key,
+			Expected:  expected,
+			Actual:    actual,
+		}
+	}
+
+	return nil
+}
+
+// StateObject representa um estado migrado encontrado no S3
+type StateObject struct {
+	WorkspaceName string
+	StateKey      string
+	MetadataKey   string
+	Metadata      map[string]interface{}
+}
+
+// ListStates lista os estados migrados da organização a partir dos arquivos metadata.json
+func (c *Client) ListStates(ctx context.Context, organization string) ([]StateObject, error) {
+	prefix := c.listPrefix(organization)
+
+	c.logger.WithField("prefix", prefix).Debug("Listando estados migrados no S3")
+
+	var states []StateObject
+
+	paginator := s3.NewListObjectsV2Paginator(c.s3Client, &s3.ListObjectsV2Input{
+		Bucket:              aws.String(c.bucket),
+		Prefix:              aws.String(prefix),
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar objetos do bucket S3 '%s': %w", c.bucket, err)
+		}
+
+		for _, object := range page.Contents {
+			key := aws.ToString(object.Key)
+			workspaceName, ok := c.parseWorkspaceName(organization, key)
+			if !ok {
+				continue
+			}
+
+			metadata, err := c.GetStateMetadata(ctx, organization, workspaceName)
+			if err != nil {
+				return nil, err
+			}
+
+			stateKey := c.generateStateKey(organization, workspaceName, c.stateFilename(workspaceName))
+			if compressed, _ := metadata["compressed"].(bool); compressed {
+				stateKey += compressedExtension
+			}
+
+			states = append(states, StateObject{
+				WorkspaceName: workspaceName,
+				StateKey:      stateKey,
+				MetadataKey:   key,
+				Metadata:      metadata,
+			})
+		}
+	}
+
+	return states, nil
+}
+
+// MigratedState resume um estado migrado para auditoria do bucket de destino
+type MigratedState struct {
+	Bucket        string    `json:"bucket"`
+	WorkspaceName string    `json:"workspace"`
+	OriginalName  string    `json:"original_name,omitempty"`
+	StateKey      string    `json:"state_key,omitempty"`
+	HasState      bool      `json:"has_state"`
+	Serial        int64     `json:"serial"`
+	MigratedAt    time.Time `json:"migrated_at"`
+	Size          int64     `json:"size"`
+}
+
+// ListMigratedStates lista os estados migrados da organização com serial, data da migração
+// (última gravação do objeto de estado) e tamanho, lidos da listagem e dos metadata.json
+func (c *Client) ListMigratedStates(ctx context.Context, organization string) ([]MigratedState, error) {
+	prefix := c.listPrefix(organization)
+
+	c.logger.WithField("prefix", prefix).Debug("Listando estados migrados no S3")
+
+	objects := make(map[string]types.Object)
+	var metadataKeys []string
+
+	paginator := s3.NewListObjectsV2Paginator(c.s3Client, &s3.ListObjectsV2Input{
+		Bucket:              aws.String(c.bucket),
+		Prefix:              aws.String(prefix),
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar objetos do bucket S3 '%s': %w", c.bucket, err)
+		}
+
+		for _, object := range page.Contents {
+			key := aws.ToString(object.Key)
+			objects[key] = object
+			if _, ok := c.parseWorkspaceName(organization, key); ok {
+				metadataKeys = append(metadataKeys, key)
+			}
+		}
+	}
+
+	states := make([]MigratedState, 0, len(metadataKeys))
+	for _, metadataKey := range metadataKeys {
+		workspaceName, _ := c.parseWorkspaceName(organization, metadataKey)
+
+		metadata, err := c.GetStateMetadata(ctx, organization, workspaceName)
+		if err != nil {
+			return nil, err
+		}
+
+		state := MigratedState{
+			Bucket:        c.bucket,
+			WorkspaceName: workspaceName,
+			MigratedAt:    aws.ToTime(objects[metadataKey].LastModified),
+		}
+		state.OriginalName, _ = metadata["workspace_name"].(string)
+		if serial, ok := metadata["serial"].(float64); ok {
+			state.Serial = int64(serial)
+		}
+
+		for _, stateKey := range c.stateKeys(organization, workspaceName) {
+			if object, ok := objects[stateKey]; ok {
+				state.StateKey = stateKey
+				state.HasState = true
+				state.Size = aws.ToInt64(object.Size)
+				state.MigratedAt = aws.ToTime(object.LastModified)
+				break
+			}
+		}
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+// GetStateMetadata lê o metadata.json de um workspace migrado
+func (c *Client) GetStateMetadata(ctx context.Context, organization, workspaceName string) (map[string]interface{}, error) {
+	metadataKey := c.generateStateKey(organization, workspaceName, c.metadataFilename(workspaceName))
+
+	if entry, ok := c.cache.get(metadataKey); ok {
+		return copyMetadata(entry.metadata), nil
+	}
+
+	// Sem metadata.json no layout atual, procura o gravado por versões anteriores
+	var output *s3.GetObjectOutput
+	var err error
+	for _, key := range c.metadataKeys(organization, workspaceName) {
+		output, err = c.s3Client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket:              aws.String(c.bucket),
+			Key:                 aws.String(key),
+			ExpectedBucketOwner: c.expectedBucketOwner(),
+		})
+		var notFound *types.NoSuchKey
+		if err == nil || !errors.As(err, &notFound) {
+			metadataKey = key
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler metadados do workspace %s: %w", workspaceName, c.kmsAccessError(ctx, metadataKey, err))
+	}
+	defer output.Body.Close()
+
+	content, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler metadados do workspace %s: %w", workspaceName, err)
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar metadados do workspace %s: %w", workspaceName, err)
+	}
+
+	c.cache.put(cacheEntry{key: c.metadataKeys(organization, workspaceName)[0], metadata: copyMetadata(metadata)})
+	return metadata, nil
+}
+
+// CreateLockEntry grava na tabela DynamoDB o digest MD5 do estado, no formato esperado pelo
+// backend S3 do Terraform (LockID "<bucket>/<key>-md5"), para que a verificação de consistência
+// passe no primeiro uso do backend
+// O digest é o MD5 em hexadecimal do conteúdo não comprimido do estado
+func (c *Client) CreateLockEntry(ctx context.Context, organization, workspaceName, digest string) error {
+	if c.dynamoClient == nil {
+		return fmt.Errorf("tabela DynamoDB não configurada (aws.dynamodb_table)")
+	}
+
+	stateKey := c.generateStateKey(organization, workspaceName, c.stateFilename(workspaceName))
+	lockID := fmt.Sprintf("%s/%s-md5", c.bucket, stateKey)
+
+	c.logger.WithFields(logrus.Fields{
+		"workspace": workspaceName,
+		"table":     c.lockTable,
+		"lock_id":   lockID,
+	}).Debug("Gravando digest do estado no DynamoDB")
+
+	_, err := c.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(c.lockTable),
+		Item: map[string]dynamodbtypes.AttributeValue{
+			"LockID": &dynamodbtypes.AttributeValueMemberS{Value: lockID},
+			"Digest": &dynamodbtypes.AttributeValueMemberS{Value: digest},
 		},
 	})
 	if err != nil {
-		return fmt.Errorf("erro ao fazer upload dos metadados do workspace %s: %w", workspaceName, err)
+		return fmt.Errorf("erro ao gravar digest do workspace %s na tabela DynamoDB '%s': %w", workspaceName, c.lockTable, err)
 	}
 
+	return nil
+}
+
+// DeleteState remove o arquivo de estado e os metadados de um workspace no S3
+func (c *Client) DeleteState(ctx context.Context, organization, workspaceName string) error {
+	stateKeys := c.stateKeys(organization, workspaceName)
+	metadataKeys := c.metadataKeys(organization, workspaceName)
+	metadataKey := metadataKeys[0]
+
 	c.logger.WithFields(logrus.Fields{
-		"workspace":     workspaceName,
-		"state_key":     stateKey,
-		"metadata_key":  metadataKey,
-	}).Info("Upload concluído com sucesso")
+		"workspace":    workspaceName,
+		"state_keys":   stateKeys,
+		"metadata_key": metadataKey,
+	}).Info("Removendo estado do S3")
+
+	defer c.cache.remove(c.baseStateKey(organization, workspaceName), metadataKey)
+
+	// Versões do histórico (--include-history) e variáveis (--include-variables) também pertencem ao workspace
+	historyKeys, err := c.listKeys(ctx, c.generateStateKey(organization, workspaceName, historyDir+"/"))
+	if err != nil {
+		return err
+	}
+	variablesKey := c.generateStateKey(organization, workspaceName, variablesFilename)
+
+	// O estado (comprimido ou não) é removido primeiro para que os metadados continuem disponíveis caso a remoção falhe
+	keys := append(append(historyKeys, variablesKey), stateKeys...)
+	for _, key := range append(keys, metadataKeys...) {
+		_, err := c.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket:              aws.String(c.bucket),
+			Key:                 aws.String(key),
+			ExpectedBucketOwner: c.expectedBucketOwner(),
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao remover objeto %s do workspace %s: %w", key, workspaceName, err)
+		}
+	}
 
 	return nil
 }
 
+// listKeys lista as chaves de todos os objetos sob o prefixo informado
+func (c *Client) listKeys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+
+	paginator := s3.NewListObjectsV2Paginator(c.s3Client, &s3.ListObjectsV2Input{
+		Bucket:              aws.String(c.bucket),
+		Prefix:              aws.String(prefix),
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar objetos do bucket S3 '%s': %w", c.bucket, err)
+		}
+
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+	}
+
+	return keys, nil
+}
+
+// DownloadState faz download do arquivo de estado de um workspace no S3
+// Estados comprimidos são descomprimidos de forma transparente
+func (c *Client) DownloadState(ctx context.Context, organization, workspaceName string) ([]byte, error) {
+	for _, stateKey := range c.stateKeys(organization, workspaceName) {
+		output, err := c.s3Client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket:              aws.String(c.bucket),
+			Key:                 aws.String(stateKey),
+			ExpectedBucketOwner: c.expectedBucketOwner(),
+		})
+		if err != nil {
+			var notFound *types.NoSuchKey
+			if errors.As(err, &notFound) {
+				continue
+			}
+			return nil, fmt.Errorf("erro ao fazer download do estado do workspace %s: %w", workspaceName, c.kmsAccessError(ctx, stateKey, err))
+		}
+
+		content, err := io.ReadAll(output.Body)
+		output.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler estado do workspace %s: %w", workspaceName, err)
+		}
+
+		if strings.HasSuffix(stateKey, compressedExtension) {
+			content, err = decompressContent(content)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao descomprimir estado do workspace %s: %w", workspaceName, err)
+			}
+		}
+
+		return content, nil
+	}
+
+	return nil, fmt.Errorf("workspace %s: %w", workspaceName, ErrStateNotFound)
+}
+
 // CheckStateExists verifica se o estado já existe no S3
+// Considera tanto a chave comprimida quanto a não comprimida
 func (c *Client) CheckStateExists(ctx context.Context, organization, workspaceName string) (bool, error) {
-	stateKey := c.generateStateKey(organization, workspaceName, "terraform.tfstate")
+	cacheKey := c.baseStateKey(organization, workspaceName)
+	if entry, ok := c.cache.get(cacheKey); ok {
+		return entry.exists, nil
+	}
 
-	_, err := c.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
-		Bucket: aws.String(c.bucket),
-		Key:    aws.String(stateKey),
-	})
-	if err != nil {
-		// Verificar se é erro "não encontrado"
-		var notFound *types.NoSuchKey
-		var notFoundBucket *types.NotFound
-		if errors.As(err, &notFound) || errors.As(err, &notFoundBucket) {
-			return false, nil
+	for _, stateKey := range c.stateKeys(organization, workspaceName) {
+		_, err := c.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket:              aws.String(c.bucket),
+			Key:                 aws.String(stateKey),
+			ExpectedBucketOwner: c.expectedBucketOwner(),
+		})
+		if err != nil {
+			// Verificar se é erro "não encontrado"
+			var notFound *types.NoSuchKey
+			var notFoundBucket *types.NotFound
+			if errors.As(err, &notFound) || errors.As(err, &notFoundBucket) {
+				continue
+			}
+			return false, fmt.Errorf("erro ao verificar existência do estado: %w", err)
 		}
-		return false, fmt.Errorf("erro ao verificar existência do estado: %w", err)
+
+		c.cache.put(cacheEntry{key: cacheKey, exists: true})
+		return true, nil
 	}
 
-	return true, nil
+	c.cache.put(cacheEntry{key: cacheKey, exists: false})
+	return false, nil
 }
 
-// uploadFile faz upload de um arquivo para S3
-func (c *Client) uploadFile(ctx context.Context, options UploadOptions) error {
+// uploadFile faz upload de um arquivo para S3 usando o transfer manager,
+// que divide objetos grandes em partes (multipart upload)
+func (c *Client) uploadFile(ctx context.Context, options UploadOptions) (*manager.UploadOutput, error) {
 	input := &s3.PutObjectInput{
-		Bucket:      aws.String(c.bucket),
-		Key:         aws.String(options.Key),
-		Body:        bytes.NewReader(options.Content),
-		ContentType: aws.String(options.ContentType),
+		Bucket:              aws.String(c.bucket),
+		Key:                 aws.String(options.Key),
+		Body:                options.Body,
+		ContentType:         aws.String(options.ContentType),
+		ChecksumAlgorithm:   types.ChecksumAlgorithmSha256,
+		ExpectedBucketOwner: c.expectedBucketOwner(),
 	}
 
 	// Adicionar metadados se fornecidos
@@ -177,17 +842,144 @@ func (c *Client) uploadFile(ctx context.Context, options UploadOptions) error {
 		input.Metadata = options.Metadata
 	}
 
-	_, err := c.s3Client.PutObject(ctx, input)
+	if c.bandwidth != nil {
+		input.Body = &throttledReader{ctx: ctx, reader: options.Body, limiter: c.bandwidth}
+	}
+
+	if options.ContentEncoding != "" {
+		input.ContentEncoding = aws.String(options.ContentEncoding)
+	}
+
+	if options.ContentMD5 != "" {
+		input.ContentMD5 = aws.String(options.ContentMD5)
+	}
+
+	if c.storageClass != "" {
+		input.StorageClass = c.storageClass
+	}
+
+	// Tags de objeto são distintas dos metadados e usadas por regras de custo e lifecycle
+	if options.Tagging != "" {
+		input.Tagging = aws.String(options.Tagging)
+	}
+
+	// Configurar criptografia no servidor
+	if options.ServerSideEncryption != "" {
+		input.ServerSideEncryption = options.ServerSideEncryption
+	}
+	if options.SSEKMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(options.SSEKMSKeyID)
+	}
+
+	output, err := c.uploader.Upload(ctx, input)
 	if err != nil {
-		return fmt.Errorf("erro ao fazer upload para S3: %w", err)
+		return nil, fmt.Errorf("erro ao fazer upload para S3: %w", redact.Error(err))
 	}
 
-	return nil
+	return output, nil
+}
+
+// withEncryption aplica SSE-KMS quando uma chave KMS está configurada, ou AES256 caso contrário
+func (c *Client) withEncryption(options UploadOptions) UploadOptions {
+	if c.kmsKeyID != "" {
+		options.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		options.SSEKMSKeyID = c.kmsKeyID
+	} else {
+		options.ServerSideEncryption = types.ServerSideEncryptionAes256
+	}
+	return options
+}
+
+// objectMetadata combina os metadados adicionais configurados com os do migrator, que têm precedência
+func (c *Client) objectMetadata(metadata map[string]string) map[string]string {
+	combined := make(map[string]string, len(c.extraMeta)+len(metadata))
+	for key, value := range c.extraMeta {
+		combined[key] = value
+	}
+	for key, value := range metadata {
+		combined[key] = value
+	}
+	return combined
+}
+
+// objectTagging monta as tags dos objetos no formato de query string exigido pelo S3
+func (c *Client) objectTagging(organization string) string {
+	tags := url.Values{}
+	for key, value := range c.objectTags {
+		tags.Set(key, value)
+	}
+	tags.Set("migrated-by", "terraform-cloud-s3-migrator")
+	tags.Set("organization", organization)
+	return tags.Encode()
+}
+
+// expectedBucketOwner retorna a conta dona do bucket, usada para falhar caso o bucket pertença a outra conta
+func (c *Client) expectedBucketOwner() *string {
+	if c.accountID == "" {
+		return nil
+	}
+	return aws.String(c.accountID)
+}
+
+// baseStateKey retorna a chave do estado sem a extensão de compressão, usada também no cache
+func (c *Client) baseStateKey(organization, workspaceName string) string {
+	return c.generateStateKey(organization, workspaceName, c.stateFilename(workspaceName))
+}
+
+// stateKeys retorna as chaves possíveis do estado, começando pela chave usada nos uploads.
+// A chave do layout anterior ao aws.key_template vem por último, para que estados migrados
+// por versões anteriores continuem sendo encontrados.
+func (c *Client) stateKeys(organization, workspaceName string) []string {
+	stateKey := c.baseStateKey(organization, workspaceName)
+	keys := []string{stateKey, stateKey + compressedExtension}
+	if c.compress {
+		keys = []string{stateKey + compressedExtension, stateKey}
+	}
+
+	if legacy := c.legacyKey(organization, workspaceName, DefaultStateFilename); legacy != "" {
+		keys = append(keys, legacy)
+	}
+	return keys
+}
+
+// metadataKeys retorna as chaves possíveis do metadata.json, a do layout atual seguida da do layout anterior
+func (c *Client) metadataKeys(organization, workspaceName string) []string {
+	keys := []string{c.generateStateKey(organization, workspaceName, c.metadataFilename(workspaceName))}
+	if legacy := c.legacyKey(organization, workspaceName, DefaultMetadataFilename); legacy != "" {
+		keys = append(keys, legacy)
+	}
+	return keys
+}
+
+// compressStream comprime o stream com gzip à medida que ele é lido.
+// Fechar o reader retornado interrompe a compressão caso o upload seja abortado.
+func compressStream(body io.Reader) io.ReadCloser {
+	reader, writer := io.Pipe()
+
+	go func() {
+		gz := gzip.NewWriter(writer)
+		if _, err := io.Copy(gz, body); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		writer.CloseWithError(gz.Close())
+	}()
+
+	return reader
 }
 
-// generateStateKey gera a chave S3 para um arquivo de estado
-func (c *Client) generateStateKey(organization, workspaceName, filename string) string {
-	// Estrutura: accountID/workspace/arquivo
-	// Exemplo: 339712781224/arcotech-aws-budget-alert/terraform.tfstate
-	return filepath.Join(c.accountID, workspaceName, filename)
-}
\ No newline at end of file
+// decompressContent descomprime conteúdo gzip, retornando o original se ele não estiver comprimido
+func decompressContent(content []byte) ([]byte, error) {
+	// O conteúdo pode já ter sido descomprimido pelo transporte HTTP devido ao Content-Encoding
+	if len(content) < 2 || content[0] != 0x1f || content[1] != 0x8b {
+		return content, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,58 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"terraform-cloud-s3-migrator/internal/redact"
+
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/smithy-go"
+)
+
+// ssoSessionErrorCodes são os códigos de erro do SSO e do SSO OIDC retornados quando o token
+// da sessão expirou e não pode ser renovado
+var ssoSessionErrorCodes = map[string]bool{
+	"UnauthorizedException": true,
+	"InvalidGrantException": true,
+	"ExpiredTokenException": true,
+}
+
+// checkCredentials obtém as credenciais AWS antes da primeira chamada ao S3, para que uma sessão
+// SSO expirada seja reportada com uma mensagem clara em vez de um erro genérico de assinatura
+func (c *Client) checkCredentials(ctx context.Context) error {
+	if c.credentials == nil {
+		return nil
+	}
+
+	if _, err := c.credentials.Retrieve(ctx); err != nil {
+		if isSSOSessionError(err) {
+			login := "aws sso login"
+			if c.profile != "" {
+				login += " --profile " + c.profile
+			}
+			return fmt.Errorf("sessão AWS SSO expirada ou inválida, execute '%s' e tente novamente: %w", login, redact.Error(err))
+		}
+		return fmt.Errorf("erro ao obter credenciais AWS: %w", redact.Error(err))
+	}
+
+	return nil
+}
+
+// isSSOSessionError indica se o erro de credenciais foi causado por uma sessão SSO expirada ou ausente
+func isSSOSessionError(err error) bool {
+	var invalidToken *ssocreds.InvalidTokenError
+	if errors.As(err, &invalidToken) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && ssoSessionErrorCodes[apiErr.ErrorCode()] {
+		return true
+	}
+
+	// O provider de token do sso_session não expõe tipos de erro próprios
+	return strings.Contains(err.Error(), "SSO token")
+}
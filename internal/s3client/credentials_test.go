@@ -0,0 +1,60 @@
+package s3client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+)
+
+// awsSSOConfig é um ~/.aws/config com um perfil que usa sso_session (aws sso login)
+const awsSSOConfig = `[profile dev]
+sso_session = corp
+sso_account_id = 123456789012
+sso_role_name = TerraformStateMigrator
+region = us-east-1
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = us-east-1
+sso_registration_scopes = sso:account:access
+`
+
+func TestNewClientSSOSessionProfile(t *testing.T) {
+	home := t.TempDir()
+	configFile := filepath.Join(home, "config")
+	if err := os.WriteFile(configFile, []byte(awsSSOConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Isolar o teste das credenciais e da configuração AWS da máquina
+	t.Setenv("HOME", home)
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(home, "credentials"))
+	for _, name := range []string{"AWS_PROFILE", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_ARN"} {
+		t.Setenv(name, "")
+	}
+
+	client, err := NewClient(Options{Region: "us-east-1", Bucket: "tfstate", Profile: "dev"})
+	if err != nil {
+		t.Fatalf("erro ao criar client com perfil sso_session: %v", err)
+	}
+
+	cache, ok := client.credentials.(*aws.CredentialsCache)
+	if !ok || !cache.IsCredentialsProvider(&ssocreds.Provider{}) {
+		t.Fatalf("credenciais do perfil sso_session não usam o provider SSO: %T", client.credentials)
+	}
+
+	// Sem token em cache (aws sso login não executado) a mensagem orienta o login no perfil
+	err = client.checkCredentials(context.Background())
+	if err == nil {
+		t.Fatal("esperado erro sem token SSO em cache")
+	}
+	if !strings.Contains(err.Error(), "aws sso login --profile dev") {
+		t.Errorf("erro não orienta o login SSO: %v", err)
+	}
+}
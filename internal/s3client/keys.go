@@ -0,0 +1,211 @@
+package s3client
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultKeyTemplate é o layout padrão das chaves no S3
+// Exemplo: terraform-states/123456789012/arcotech-aws-budget-alert/terraform.tfstate
+const DefaultKeyTemplate = "{prefix}{account_id}/{workspace}/{filename}"
+
+// Nomes padrão dos arquivos de estado e de metadados de cada workspace
+const (
+	DefaultStateFilename    = "terraform.tfstate"
+	DefaultMetadataFilename = "metadata.json"
+)
+
+// Placeholders aceitos em aws.key_template
+const (
+	placeholderPrefix       = "{prefix}"
+	placeholderOrganization = "{organization}"
+	placeholderAccountID    = "{account_id}"
+	placeholderWorkspace    = "{workspace}"
+	placeholderFilename     = "{filename}"
+)
+
+// Sentinelas que marcam a posição do workspace e da organização ao derivar prefixos e padrões do template
+const (
+	workspaceSentinel    = "\x00"
+	organizationSentinel = "\x01"
+)
+
+// legacyAccountID é o valor padrão de aws.accountid nas versões anteriores ao aws.key_template,
+// que gravavam os arquivos em {account_id}/{workspace}/{arquivo}, sem o prefixo
+const legacyAccountID = "339712781224"
+
+// normalizePrefix remove a barra inicial e garante uma única barra final no prefixo
+func normalizePrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "/"
+}
+
+// KeyLayout gera as chaves dos objetos de um workspace a partir de aws.key_template.
+// Não depende de um client S3, então também é usado para gravar arquivos no mesmo layout em disco.
+type KeyLayout struct {
+	prefix       string
+	accountID    string
+	keyTemplate  string
+	stateFile    string
+	metadataFile string
+
+	// Padrão das chaves de metadata.json, compilado uma única vez em NewKeyLayout
+	metadataPattern *keyPattern
+}
+
+// keyPattern reconhece as chaves geradas pelo template e extrai o workspace e a organização
+type keyPattern struct {
+	re     *regexp.Regexp
+	groups []string // Sentinela correspondente a cada grupo capturado, na ordem
+}
+
+// NewKeyLayout cria o layout a partir do prefixo, conta, template e nomes de arquivo das opções,
+// aplicando os valores padrão aos campos vazios
+func NewKeyLayout(options Options) KeyLayout {
+	layout := KeyLayout{
+		prefix:       normalizePrefix(options.Prefix),
+		accountID:    options.AccountID,
+		keyTemplate:  options.KeyTemplate,
+		stateFile:    options.StateFilename,
+		metadataFile: options.MetadataFilename,
+	}
+
+	if layout.keyTemplate == "" {
+		layout.keyTemplate = DefaultKeyTemplate
+	}
+	if layout.stateFile == "" {
+		layout.stateFile = DefaultStateFilename
+	}
+	if layout.metadataFile == "" {
+		layout.metadataFile = DefaultMetadataFilename
+	}
+
+	layout.metadataPattern = compileKeyPattern(layout.generateStateKey(organizationSentinel, workspaceSentinel, layout.metadataFilename(workspaceSentinel)))
+
+	return layout
+}
+
+// compileKeyPattern converte uma chave gerada com as sentinelas em uma expressão regular,
+// com um grupo para cada ocorrência do workspace e da organização
+func compileKeyPattern(key string) *keyPattern {
+	pattern := &keyPattern{}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	literal := 0
+	for i := 0; i < len(key); i++ {
+		sentinel := key[i : i+1]
+		if sentinel != workspaceSentinel && sentinel != organizationSentinel {
+			continue
+		}
+
+		expr.WriteString(regexp.QuoteMeta(key[literal:i]))
+		expr.WriteString(`([^/]+)`)
+		pattern.groups = append(pattern.groups, sentinel)
+		literal = i + 1
+	}
+	expr.WriteString(regexp.QuoteMeta(key[literal:]))
+	expr.WriteString("$")
+
+	pattern.re = regexp.MustCompile(expr.String())
+	return pattern
+}
+
+// StatePath retorna a chave do estado (não comprimido) do workspace
+func (l KeyLayout) StatePath(organization, workspaceName string) string {
+	return l.generateStateKey(organization, workspaceName, l.stateFilename(workspaceName))
+}
+
+// MetadataPath retorna a chave do metadata.json do workspace
+func (l KeyLayout) MetadataPath(organization, workspaceName string) string {
+	return l.generateStateKey(organization, workspaceName, l.metadataFilename(workspaceName))
+}
+
+// cleanKey remove barras iniciais e duplicadas da chave gerada
+func cleanKey(key string) string {
+	for strings.Contains(key, "//") {
+		key = strings.ReplaceAll(key, "//", "/")
+	}
+	return strings.TrimPrefix(key, "/")
+}
+
+// generateStateKey gera a chave S3 de um arquivo do workspace a partir do template configurado
+func (l KeyLayout) generateStateKey(organization, workspaceName, filename string) string {
+	return cleanKey(l.keyReplacer(organization, workspaceName, filename).Replace(l.keyTemplate))
+}
+
+// keyReplacer substitui os placeholders do template pelos valores informados
+func (l KeyLayout) keyReplacer(organization, workspaceName, filename string) *strings.Replacer {
+	return strings.NewReplacer(
+		placeholderPrefix, l.prefix,
+		placeholderOrganization, organization,
+		placeholderAccountID, l.accountID,
+		placeholderWorkspace, workspaceName,
+		placeholderFilename, filename,
+	)
+}
+
+// stateFilename retorna o nome do arquivo de estado do workspace, substituindo {workspace} no template
+func (l KeyLayout) stateFilename(workspaceName string) string {
+	return strings.ReplaceAll(l.stateFile, placeholderWorkspace, workspaceName)
+}
+
+// metadataFilename retorna o nome do arquivo de metadados do workspace, substituindo {workspace} no template
+func (l KeyLayout) metadataFilename(workspaceName string) string {
+	return strings.ReplaceAll(l.metadataFile, placeholderWorkspace, workspaceName)
+}
+
+// listPrefix retorna a parte fixa da chave, anterior ao nome do workspace, usada para listar objetos
+func (l KeyLayout) listPrefix(organization string) string {
+	key := l.generateStateKey(organization, workspaceSentinel, "")
+	return key[:strings.Index(key, workspaceSentinel)]
+}
+
+// parseWorkspaceName extrai o nome do workspace de uma chave de metadata.json da organização
+func (l KeyLayout) parseWorkspaceName(organization, key string) (string, bool) {
+	matches := l.metadataPattern.re.FindStringSubmatch(key)
+	if matches == nil {
+		return "", false
+	}
+
+	// O workspace pode aparecer mais de uma vez (ex: {workspace} também no nome do arquivo)
+	workspaceName := ""
+	for i, sentinel := range l.metadataPattern.groups {
+		match := matches[i+1]
+		switch {
+		case sentinel == organizationSentinel:
+			if match != organization {
+				return "", false
+			}
+		case workspaceName == "":
+			workspaceName = match
+		case match != workspaceName:
+			return "", false
+		}
+	}
+
+	return workspaceName, workspaceName != ""
+}
+
+// legacyKey retorna a chave de um arquivo no layout anterior ao aws.key_template, usada apenas para
+// encontrar estados migrados por versões anteriores. Retorna "" quando um template foi configurado
+// ou quando a chave coincide com a do layout atual.
+func (l KeyLayout) legacyKey(organization, workspaceName, filename string) string {
+	if l.keyTemplate != DefaultKeyTemplate {
+		return ""
+	}
+
+	accountID := l.accountID
+	if accountID == "" {
+		accountID = legacyAccountID
+	}
+
+	key := cleanKey(accountID + "/" + workspaceName + "/" + filename)
+	if key == l.generateStateKey(organization, workspaceName, filename) {
+		return ""
+	}
+	return key
+}
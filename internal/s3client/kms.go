@@ -0,0 +1,56 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"terraform-cloud-s3-migrator/internal/redact"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// kmsAccessError explica a recusa de leitura de um objeto criptografado com SSE-KMS.
+// A descriptografia é feita pelo S3, mas exige kms:Decrypt na chave para quem lê o objeto:
+// sem ela o HeadObject funciona e o GetObject falha com um AccessDenied genérico. O erro
+// original é mantido na cadeia para a classificação de retry e de autenticação.
+func (c *Client) kmsAccessError(ctx context.Context, key string, err error) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "AccessDenied" {
+		return redact.Error(err)
+	}
+
+	keyID, encrypted := c.objectKMSKey(ctx, key, apiErr.ErrorMessage())
+	if !encrypted {
+		return redact.Error(err)
+	}
+
+	if keyID == "" {
+		keyID = "do objeto"
+	}
+	return fmt.Errorf("AccessDenied: sem permissão kms:Decrypt na chave KMS %s para ler %s, conceda kms:Decrypt ao usuário ou role em uso: %w", keyID, key, redact.Error(err))
+}
+
+// objectKMSKey indica se o objeto está criptografado com SSE-KMS e retorna a chave usada.
+// A mensagem do AccessDenied já cita o KMS quando a recusa vem da chave; caso contrário o
+// HeadObject, que não exige kms:Decrypt, confirma a criptografia do objeto.
+func (c *Client) objectKMSKey(ctx context.Context, key, message string) (string, bool) {
+	mentionsKMS := strings.Contains(strings.ToLower(message), "kms")
+
+	output, err := c.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:              aws.String(c.bucket),
+		Key:                 aws.String(key),
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+	if err != nil {
+		return c.kmsKeyID, mentionsKMS
+	}
+
+	encrypted := output.ServerSideEncryption == types.ServerSideEncryptionAwsKms ||
+		output.ServerSideEncryption == types.ServerSideEncryptionAwsKmsDsse
+	return aws.ToString(output.SSEKMSKeyId), encrypted || mentionsKMS
+}
@@ -0,0 +1,72 @@
+package s3client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// manifestFilename é o nome do manifesto gravado ao lado das pastas dos workspaces
+const manifestFilename = "_manifest.json"
+
+// ErrManifestNotFound indica que o manifesto ainda não existe no S3
+var ErrManifestNotFound = errors.New("manifesto não encontrado no S3")
+
+// StateKey retorna a chave usada no upload do estado do workspace
+func (c *Client) StateKey(organization, workspaceName string) string {
+	return c.stateKeys(organization, workspaceName)[0]
+}
+
+// ManifestKey retorna a chave do manifesto da organização
+func (c *Client) ManifestKey(organization string) string {
+	return c.listPrefix(organization) + manifestFilename
+}
+
+// UploadManifest grava o manifesto da organização no S3
+func (c *Client) UploadManifest(ctx context.Context, organization string, content []byte) error {
+	_, err := c.uploadFile(ctx, c.withEncryption(UploadOptions{
+		Key:         c.ManifestKey(organization),
+		Body:        bytes.NewReader(content),
+		ContentType: "application/json",
+		Metadata: map[string]string{
+			"organization": organization,
+			"file-type":    "manifest",
+		},
+		Tagging: c.objectTagging(organization),
+	}))
+	if err != nil {
+		return fmt.Errorf("erro ao fazer upload do manifesto: %w", err)
+	}
+
+	return nil
+}
+
+// DownloadManifest lê o manifesto da organização do S3
+func (c *Client) DownloadManifest(ctx context.Context, organization string) ([]byte, error) {
+	output, err := c.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:              aws.String(c.bucket),
+		Key:                 aws.String(c.ManifestKey(organization)),
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+	if err != nil {
+		var notFound *types.NoSuchKey
+		if errors.As(err, &notFound) {
+			return nil, ErrManifestNotFound
+		}
+		return nil, fmt.Errorf("erro ao fazer download do manifesto: %w", err)
+	}
+	defer output.Body.Close()
+
+	content, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler manifesto: %w", err)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,101 @@
+package s3client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"terraform-cloud-s3-migrator/internal/redact"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// healthcheckKey é o objeto gravado e removido por ProbeWrite, dentro de aws.prefix
+const healthcheckKey = "_healthcheck"
+
+// CheckEncryption retorna a criptografia padrão configurada no bucket (algoritmo e chave KMS, se houver)
+func (c *Client) CheckEncryption(ctx context.Context) (string, error) {
+	output, err := c.s3Client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{
+		Bucket:              aws.String(c.bucket),
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("erro ao obter a criptografia padrão do bucket S3 '%s': %w", c.bucket, redact.Error(err))
+	}
+
+	if output.ServerSideEncryptionConfiguration == nil || len(output.ServerSideEncryptionConfiguration.Rules) == 0 {
+		return "", fmt.Errorf("bucket S3 '%s' não possui criptografia padrão configurada", c.bucket)
+	}
+
+	rule := output.ServerSideEncryptionConfiguration.Rules[0].ApplyServerSideEncryptionByDefault
+	if rule == nil {
+		return "", fmt.Errorf("bucket S3 '%s' não possui criptografia padrão configurada", c.bucket)
+	}
+
+	if keyID := aws.ToString(rule.KMSMasterKeyID); keyID != "" {
+		return fmt.Sprintf("%s (%s)", rule.SSEAlgorithm, keyID), nil
+	}
+	return string(rule.SSEAlgorithm), nil
+}
+
+// ProbeWrite confirma as permissões de escrita, leitura e remoção no bucket gravando um objeto
+// pequeno com a mesma criptografia dos estados, lendo-o de volta e removendo-o em seguida.
+// Em buckets versionados a versão gravada é removida, para não deixar versões nem delete markers.
+func (c *Client) ProbeWrite(ctx context.Context) error {
+	key := c.prefix + healthcheckKey
+	content := []byte("ok\n")
+
+	output, err := c.uploadFile(ctx, c.withEncryption(UploadOptions{
+		Key:         key,
+		Body:        bytes.NewReader(content),
+		ContentType: "text/plain",
+		Metadata:    c.objectMetadata(map[string]string{"file-type": "healthcheck"}),
+	}))
+	if err != nil {
+		return fmt.Errorf("sem permissão de escrita em s3://%s/%s: %w", c.bucket, key, err)
+	}
+
+	// A remoção é tentada mesmo se a leitura falhar, para não deixar o objeto no bucket
+	readErr := c.probeRead(ctx, key, content)
+
+	_, err = c.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:              aws.String(c.bucket),
+		Key:                 aws.String(key),
+		VersionId:           output.VersionID,
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+	if readErr != nil {
+		return readErr
+	}
+	if err != nil {
+		return fmt.Errorf("sem permissão de remoção em s3://%s/%s: %w", c.bucket, key, redact.Error(err))
+	}
+
+	return nil
+}
+
+// probeRead lê o objeto gravado por ProbeWrite e confere o conteúdo
+func (c *Client) probeRead(ctx context.Context, key string, expected []byte) error {
+	output, err := c.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:              aws.String(c.bucket),
+		Key:                 aws.String(key),
+		ExpectedBucketOwner: c.expectedBucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("sem permissão de leitura em s3://%s/%s: %w", c.bucket, key, c.kmsAccessError(ctx, key, err))
+	}
+	defer output.Body.Close()
+
+	content, err := io.ReadAll(output.Body)
+	if err != nil {
+		return fmt.Errorf("erro ao ler s3://%s/%s: %w", c.bucket, key, err)
+	}
+
+	if !bytes.Equal(content, expected) {
+		return fmt.Errorf("conteúdo lido de s3://%s/%s difere do gravado", c.bucket, key)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package s3client
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+)
+
+// maxThrottleBurst limita o tamanho de cada leitura controlada pelo limitador de banda
+const maxThrottleBurst = 256 * 1024
+
+// NewBandwidthLimiter cria o limitador de banda compartilhado pelos uploads a partir do limite
+// em megabits por segundo. Retorna nil, sem limite, para valores menores ou iguais a zero.
+func NewBandwidthLimiter(mbps float64) *rate.Limiter {
+	if mbps <= 0 {
+		return nil
+	}
+
+	bytesPerSecond := mbps * 1000 * 1000 / 8
+	burst := maxThrottleBurst
+	if bytesPerSecond < float64(burst) {
+		burst = max(int(bytesPerSecond), 1)
+	}
+
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+}
+
+// throttledReader limita a taxa de leitura do body enviado ao S3. O limitador é compartilhado
+// entre todos os uploads, então o limite vale para a soma das transferências simultâneas.
+type throttledReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	limiter *rate.Limiter
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > r.limiter.Burst() {
+		p = p[:r.limiter.Burst()]
+	}
+
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.WaitN(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
@@ -2,41 +2,115 @@ package terraform
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"terraform-cloud-s3-migrator/internal/redact"
+	"terraform-cloud-s3-migrator/internal/retry"
 
 	tfe "github.com/hashicorp/go-tfe"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 )
 
+// StateSource identifica, nos metadados enviados ao S3, estados migrados por esta ferramenta
+const StateSource = "terraform_cloud"
+
+// HTTPError representa uma resposta HTTP inesperada ao fazer download do estado
+type HTTPError struct {
+	StatusCode    int
+	WorkspaceName string
+	RetryAfterDur time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("erro HTTP %d ao fazer download do estado do workspace %s", e.StatusCode, e.WorkspaceName)
+}
+
+// HTTPStatusCode retorna o status HTTP da resposta, usado para classificar retentativas
+func (e *HTTPError) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// RetryAfter retorna o tempo de espera indicado pelo header Retry-After (zero se ausente)
+func (e *HTTPError) RetryAfter() time.Duration {
+	return e.RetryAfterDur
+}
+
 type Client struct {
 	client       *tfe.Client
 	organization string
 	token        string
+	limiter      *rate.Limiter
+	backoff      retry.Backoff
+	httpClient   *http.Client
+	maxStateSize int64
 	logger       *logrus.Entry
 }
 
+// Options agrupa as configurações usadas para criar o client do Terraform Cloud
+type Options struct {
+	Token        string
+	Organization string
+	Address      string // Vazio usa o endpoint público (app.terraform.io)
+
+	// Limite global de requisições por segundo (zero desativa o limite)
+	RequestsPerSecond float64
+
+	// Política de retentativas das chamadas paginadas à API
+	Backoff retry.Backoff
+
+	// Tempo máximo de cada download de estado, incluindo a leitura do conteúdo (zero desativa)
+	DownloadTimeout time.Duration
+
+	// Tamanho máximo em bytes de um estado baixado (zero desativa)
+	MaxStateSize int64
+}
+
 type Workspace struct {
-	ID                   string
-	Name                 string
-	Description          string
-	CurrentStateVersion  string
-	HasState             bool
+	ID                  string   `json:"id"`
+	Name                string   `json:"name"`
+	Description         string   `json:"description,omitempty"`
+	CurrentStateVersion string   `json:"current_state_version,omitempty"`
+	HasState            bool     `json:"has_state"`
+	Tags                []string `json:"tags,omitempty"`
+	ProjectID           string   `json:"project_id,omitempty"`
+	ProjectName         string   `json:"project_name,omitempty"`
+	ResourceCount       int      `json:"resource_count"` // Informado pelo Terraform Cloud
+	Locked              bool     `json:"locked"`         // Travado por um run em andamento ou manualmente
+}
+
+// WorkspaceFilter define filtros aplicados pelo Terraform Cloud na listagem de workspaces
+type WorkspaceFilter struct {
+	// Tags que o workspace deve possuir (todas)
+	Tags []string
+	// ID do projeto do Terraform Cloud ao qual o workspace deve pertencer
+	ProjectID string
 }
 
 type StateData struct {
 	WorkspaceName string
-	StateContent  []byte
+	StateContent  []byte // Preenchido apenas por GetWorkspaceState
+	Size          int64  // Tamanho em bytes, ou -1 se desconhecido antes da leitura do stream
 	Version       int
 	StateID       string
 	Metadata      map[string]interface{}
 }
 
 // NewClient cria um novo client para o Terraform Cloud
-func NewClient(token, organization string) (*Client, error) {
+func NewClient(options Options) (*Client, error) {
 	config := &tfe.Config{
-		Token: token,
+		Token: options.Token,
+	}
+
+	if options.Address != "" {
+		config.Address = options.Address
 	}
 
 	client, err := tfe.NewClient(config)
@@ -46,50 +120,62 @@ func NewClient(token, organization string) (*Client, error) {
 
 	logger := logrus.WithFields(logrus.Fields{
 		"component":    "terraform-client",
-		"organization": organization,
+		"organization": options.Organization,
 	})
 
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	if options.RequestsPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(options.RequestsPerSecond), 1)
+	}
+
 	return &Client{
 		client:       client,
-		organization: organization,
-		token:        token,
+		organization: options.Organization,
+		token:        options.Token,
+		limiter:      limiter,
+		backoff:      options.Backoff,
+		httpClient:   &http.Client{Timeout: options.DownloadTimeout},
+		maxStateSize: options.MaxStateSize,
 		logger:       logger,
 	}, nil
 }
 
-// ListWorkspaces lista todos os workspaces da organização
-func (c *Client) ListWorkspaces(ctx context.Context) ([]Workspace, error) {
+// MaxPageSize é o maior tamanho de página aceito pela API do Terraform Cloud
+const MaxPageSize = 100
+
+// WorkspacePage é uma página da listagem de workspaces
+type WorkspacePage struct {
+	Workspaces []Workspace `json:"workspaces"`
+	Page       int         `json:"page"`
+	TotalPages int         `json:"total_pages"`
+	TotalCount int         `json:"total_count"`
+}
+
+// ListWorkspaces lista todos os workspaces da organização que atendem ao filtro
+func (c *Client) ListWorkspaces(ctx context.Context, filter WorkspaceFilter) ([]Workspace, error) {
 	c.logger.Info("Listando workspaces")
 
-	options := &tfe.WorkspaceListOptions{
-		ListOptions: tfe.ListOptions{
-			PageSize: 100,
-		},
-	}
+	options := workspaceListOptions(filter, MaxPageSize)
 
 	var allWorkspaces []Workspace
 
-	for {
-		workspaces, err := c.client.Workspaces.List(ctx, c.organization, options)
+	for page := 1; ; page++ {
+		// Cada página é retentada isoladamente para não perder as páginas já obtidas
+		workspaces, err := c.listWorkspacesPage(ctx, options, page)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao listar workspaces: %w", err)
+			return nil, err
 		}
 
 		for _, ws := range workspaces.Items {
-			workspace := Workspace{
-				ID:          ws.ID,
-				Name:        ws.Name,
-				Description: ws.Description,
-				HasState:    ws.CurrentStateVersion != nil,
-			}
-
-			if ws.CurrentStateVersion != nil {
-				workspace.CurrentStateVersion = ws.CurrentStateVersion.ID
-			}
-
-			allWorkspaces = append(allWorkspaces, workspace)
+			allWorkspaces = append(allWorkspaces, newWorkspace(ws))
 		}
 
+		c.logger.WithFields(logrus.Fields{
+			"page":       page,
+			"workspaces": len(workspaces.Items),
+			"total":      len(allWorkspaces),
+		}).Debug("Página de workspaces obtida")
+
 		if workspaces.NextPage == 0 {
 			break
 		}
@@ -100,55 +186,145 @@ func (c *Client) ListWorkspaces(ctx context.Context) ([]Workspace, error) {
 	return allWorkspaces, nil
 }
 
-// GetWorkspaceState obtém o estado atual de um workspace
+// ListWorkspacesPage obtém apenas a página informada (a partir de 1) da listagem de workspaces,
+// com até size itens, sem percorrer as demais páginas
+func (c *Client) ListWorkspacesPage(ctx context.Context, page, size int) (*WorkspacePage, error) {
+	if page < 1 {
+		return nil, fmt.Errorf("página inválida: %d", page)
+	}
+	if size < 1 || size > MaxPageSize {
+		return nil, fmt.Errorf("tamanho de página deve estar entre 1 e %d: %d", MaxPageSize, size)
+	}
+
+	options := workspaceListOptions(WorkspaceFilter{}, size)
+	options.PageNumber = page
+
+	workspaces, err := c.listWorkspacesPage(ctx, options, page)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WorkspacePage{
+		Workspaces: make([]Workspace, 0, len(workspaces.Items)),
+		Page:       page,
+	}
+	for _, ws := range workspaces.Items {
+		result.Workspaces = append(result.Workspaces, newWorkspace(ws))
+	}
+	if workspaces.Pagination != nil {
+		result.TotalPages = workspaces.Pagination.TotalPages
+		result.TotalCount = workspaces.Pagination.TotalCount
+	}
+
+	return result, nil
+}
+
+// workspaceListOptions monta as opções de listagem a partir do filtro
+func workspaceListOptions(filter WorkspaceFilter, size int) *tfe.WorkspaceListOptions {
+	options := &tfe.WorkspaceListOptions{
+		ListOptions: tfe.ListOptions{
+			PageSize: size,
+		},
+		// Incluir o projeto para exibir o nome sem uma requisição extra por workspace
+		Include:   []tfe.WSIncludeOpt{tfe.WSProject},
+		ProjectID: filter.ProjectID,
+	}
+
+	if len(filter.Tags) > 0 {
+		options.Tags = strings.Join(filter.Tags, ",")
+	}
+
+	return options
+}
+
+// listWorkspacesPage obtém uma página da listagem, retentando falhas transitórias
+func (c *Client) listWorkspacesPage(ctx context.Context, options *tfe.WorkspaceListOptions, page int) (*tfe.WorkspaceList, error) {
+	var workspaces *tfe.WorkspaceList
+	err := retry.Do(ctx, c.backoff, func() error {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		var err error
+		workspaces, err = c.client.Workspaces.List(ctx, c.organization, options)
+		return classifyAPIError(err)
+	}, func(attempt int, delay time.Duration, err error) {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"page":    page,
+			"attempt": attempt,
+		}).Warnf("Falha ao listar página de workspaces, tentando novamente em %v", delay)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar workspaces (página %d): %w", page, redact.Error(err))
+	}
+
+	return workspaces, nil
+}
+
+// GetWorkspaceState obtém o estado atual de um workspace com o conteúdo completo em memória
 func (c *Client) GetWorkspaceState(ctx context.Context, workspaceID string) (*StateData, error) {
+	stateData, body, err := c.OpenWorkspaceState(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	stateContent, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler conteúdo do estado do workspace %s: %w", stateData.WorkspaceName, redact.Error(err))
+	}
+	stateData.StateContent = stateContent
+	stateData.Size = int64(len(stateContent))
+
+	c.logger.WithFields(logrus.Fields{
+		"workspace_name": stateData.WorkspaceName,
+		"state_version":  stateData.Version,
+		"size_bytes":     stateData.Size,
+	}).Debug("Estado obtido com sucesso")
+
+	return stateData, nil
+}
+
+// OpenWorkspaceState obtém o estado atual de um workspace sem carregá-lo em memória.
+// O chamador deve fechar o stream retornado.
+func (c *Client) OpenWorkspaceState(ctx context.Context, workspaceID string) (*StateData, io.ReadCloser, error) {
 	c.logger.WithField("workspace_id", workspaceID).Debug("Obtendo estado do workspace")
 
 	// Primeiro, obter o workspace para verificar se tem estado
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, nil, err
+	}
 	workspace, err := c.client.Workspaces.ReadByID(ctx, workspaceID)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao ler workspace %s: %w", workspaceID, err)
+		return nil, nil, fmt.Errorf("erro ao ler workspace %s: %w", workspaceID, redact.Error(err))
 	}
 
 	if workspace.CurrentStateVersion == nil {
-		return nil, fmt.Errorf("workspace %s não possui estado atual", workspace.Name)
+		return nil, nil, fmt.Errorf("workspace %s não possui estado atual", workspace.Name)
 	}
 
 	// Obter a versão do estado
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, nil, err
+	}
 	stateVersion, err := c.client.StateVersions.ReadCurrent(ctx, workspaceID)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao ler versão do estado para workspace %s: %w", workspace.Name, err)
+		return nil, nil, fmt.Errorf("erro ao ler versão do estado para workspace %s: %w", workspace.Name, redact.Error(err))
 	}
 
 	// Download do conteúdo do estado
 	stateURL := stateVersion.DownloadURL
 	if stateURL == "" {
-		return nil, fmt.Errorf("URL de download não disponível para o estado do workspace %s", workspace.Name)
+		return nil, nil, &refreshableURLError{Err: fmt.Errorf("URL de download não disponível para o estado do workspace %s", workspace.Name)}
 	}
 
-	// Fazer download do arquivo de estado
-	req, err := http.NewRequest("GET", stateURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar requisição para download do estado: %w", err)
-	}
-	
-	// Adicionar token de autenticação
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao fazer download do estado do workspace %s: %w", workspace.Name, err)
-	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("erro HTTP %d ao fazer download do estado do workspace %s", resp.StatusCode, workspace.Name)
+	resp, err := c.download(ctx, stateURL, workspace.Name)
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+		return nil, nil, &refreshableURLError{Err: err}
 	}
-
-	stateContent, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao ler conteúdo do estado do workspace %s: %w", workspace.Name, err)
+		return nil, nil, err
 	}
 
 	// Preparar metadata
@@ -160,7 +336,7 @@ func (c *Client) GetWorkspaceState(ctx context.Context, workspaceID string) (*St
 		"serial":             stateVersion.Serial,
 		"created_at":         stateVersion.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		"terraform_version":  stateVersion.TerraformVersion,
-		"source":            "terraform_cloud",
+		"source":            StateSource,
 	}
 
 	if stateVersion.VCSCommitSHA != "" {
@@ -169,53 +345,319 @@ func (c *Client) GetWorkspaceState(ctx context.Context, workspaceID string) (*St
 
 	stateData := &StateData{
 		WorkspaceName: workspace.Name,
-		StateContent:  stateContent,
+		Size:          resp.ContentLength,
 		Version:       int(stateVersion.Serial),
 		StateID:       stateVersion.ID,
 		Metadata:      metadata,
 	}
 
-	c.logger.WithFields(logrus.Fields{
-		"workspace_name": workspace.Name,
-		"state_version":  stateVersion.Serial,
-		"size_bytes":     len(stateContent),
-	}).Debug("Estado obtido com sucesso")
+	return stateData, resp.Body, nil
+}
 
-	return stateData, nil
+// GetCurrentSerial obtém o serial da versão atual do estado de um workspace
+func (c *Client) GetCurrentSerial(ctx context.Context, workspaceID string) (int64, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return 0, err
+	}
+
+	stateVersion, err := c.client.StateVersions.ReadCurrent(ctx, workspaceID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao ler versão do estado do workspace %s: %w", workspaceID, redact.Error(err))
+	}
+
+	return stateVersion.Serial, nil
+}
+
+// GetCurrentStateVersion obtém os dados da versão atual do estado de um workspace, sem o conteúdo
+func (c *Client) GetCurrentStateVersion(ctx context.Context, workspaceID string) (*StateVersion, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	stateVersion, err := c.client.StateVersions.ReadCurrent(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler versão do estado do workspace %s: %w", workspaceID, redact.Error(err))
+	}
+
+	return &StateVersion{
+		ID:          stateVersion.ID,
+		Serial:      stateVersion.Serial,
+		CreatedAt:   stateVersion.CreatedAt,
+		DownloadURL: stateVersion.DownloadURL,
+	}, nil
+}
+
+// stateVersionSize é o subconjunto da versão de estado com o tamanho informado pela API,
+// que o go-tfe não expõe
+type stateVersionSize struct {
+	ID   string `jsonapi:"primary,state-versions"`
+	Size int64  `jsonapi:"attr,size"`
+}
+
+// GetCurrentStateSize retorna o tamanho em bytes da versão atual do estado informado pela API,
+// sem fazer o download do conteúdo
+func (c *Client) GetCurrentStateSize(ctx context.Context, workspaceID string) (int64, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return 0, err
+	}
+
+	req, err := c.client.NewRequest(http.MethodGet, fmt.Sprintf("workspaces/%s/current-state-version", url.PathEscape(workspaceID)), nil)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao criar requisição da versão do estado do workspace %s: %w", workspaceID, err)
+	}
+
+	version := &stateVersionSize{}
+	if err := req.Do(ctx, version); err != nil {
+		return 0, fmt.Errorf("erro ao ler tamanho do estado do workspace %s: %w", workspaceID, redact.Error(err))
+	}
+
+	return version.Size, nil
+}
+
+// StateVersion representa uma versão do histórico de estados de um workspace
+type StateVersion struct {
+	ID          string
+	Serial      int64
+	CreatedAt   time.Time
+	DownloadURL string
+}
+
+// ListStateVersions lista todas as versões de estado de um workspace, da mais recente para a mais antiga
+func (c *Client) ListStateVersions(ctx context.Context, workspaceName string) ([]StateVersion, error) {
+	options := &tfe.StateVersionListOptions{
+		ListOptions: tfe.ListOptions{
+			PageSize: 100,
+		},
+		Organization: c.organization,
+		Workspace:    workspaceName,
+	}
+
+	var versions []StateVersion
+
+	for {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		list, err := c.client.StateVersions.List(ctx, options)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar versões de estado do workspace %s: %w", workspaceName, redact.Error(err))
+		}
+
+		for _, sv := range list.Items {
+			versions = append(versions, StateVersion{
+				ID:          sv.ID,
+				Serial:      sv.Serial,
+				CreatedAt:   sv.CreatedAt,
+				DownloadURL: sv.DownloadURL,
+			})
+		}
+
+		if list.Pagination == nil || list.NextPage == 0 {
+			break
+		}
+		options.PageNumber = list.NextPage
+	}
+
+	return versions, nil
+}
+
+// OpenStateVersion obtém o conteúdo de uma versão de estado sem carregá-lo em memória.
+// O chamador deve fechar o stream retornado.
+func (c *Client) OpenStateVersion(ctx context.Context, workspaceName string, version StateVersion) (io.ReadCloser, error) {
+	if version.DownloadURL == "" {
+		return nil, fmt.Errorf("URL de download não disponível para a versão %s do workspace %s", version.ID, workspaceName)
+	}
+
+	resp, err := c.download(ctx, version.DownloadURL, workspaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// download faz o download autenticado de um arquivo de estado
+func (c *Client) download(ctx context.Context, stateURL, workspaceName string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, stateURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição para download do estado: %w", redact.Error(err))
+	}
+
+	// Adicionar token de autenticação
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao fazer download do estado do workspace %s: %w", workspaceName, redact.Error(err))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &HTTPError{
+			StatusCode:    resp.StatusCode,
+			WorkspaceName: workspaceName,
+			RetryAfterDur: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	if c.maxStateSize > 0 {
+		if resp.ContentLength > c.maxStateSize {
+			resp.Body.Close()
+			return nil, &StateTooLargeError{WorkspaceName: workspaceName, Limit: c.maxStateSize}
+		}
+		resp.Body = newLimitedBody(resp.Body, workspaceName, c.maxStateSize)
+	}
+
+	return resp, nil
 }
 
 // GetWorkspaceByName obtém um workspace pelo nome
 func (c *Client) GetWorkspaceByName(ctx context.Context, name string) (*Workspace, error) {
 	c.logger.WithField("workspace_name", name).Debug("Buscando workspace por nome")
 
-	workspace, err := c.client.Workspaces.Read(ctx, c.organization, name)
+	var workspace *tfe.Workspace
+	err := retry.Do(ctx, c.backoff, func() error {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		var err error
+		workspace, err = c.client.Workspaces.ReadWithOptions(ctx, c.organization, name, &tfe.WorkspaceReadOptions{
+			Include: []tfe.WSIncludeOpt{tfe.WSProject},
+		})
+		return classifyAPIError(err)
+	}, func(attempt int, delay time.Duration, err error) {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"workspace_name": name,
+			"attempt":        attempt,
+		}).Warnf("Falha ao buscar workspace, tentando novamente em %v", delay)
+	})
+	if errors.Is(err, tfe.ErrResourceNotFound) {
+		return nil, fmt.Errorf("workspace %s: %w", name, ErrWorkspaceNotFound)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar workspace %s: %w", name, err)
+		return nil, fmt.Errorf("erro ao buscar workspace %s: %w", name, redact.Error(err))
 	}
 
-	ws := &Workspace{
-		ID:          workspace.ID,
-		Name:        workspace.Name,
-		Description: workspace.Description,
-		HasState:    workspace.CurrentStateVersion != nil,
+	ws := newWorkspace(workspace)
+	return &ws, nil
+}
+
+// GetProjectID retorna o ID do projeto do Terraform Cloud com o nome informado
+func (c *Client) GetProjectID(ctx context.Context, name string) (string, error) {
+	c.logger.WithField("project_name", name).Debug("Buscando projeto por nome")
+
+	options := &tfe.ProjectListOptions{
+		ListOptions: tfe.ListOptions{
+			PageSize: 100,
+		},
+		Name: name,
 	}
 
-	if workspace.CurrentStateVersion != nil {
-		ws.CurrentStateVersion = workspace.CurrentStateVersion.ID
+	for {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return "", err
+		}
+
+		projects, err := c.client.Projects.List(ctx, c.organization, options)
+		if err != nil {
+			return "", fmt.Errorf("erro ao buscar projeto %s: %w", name, redact.Error(err))
+		}
+
+		// O filtro por nome da API não é exato, então comparar o nome completo
+		for _, project := range projects.Items {
+			if project.Name == name {
+				return project.ID, nil
+			}
+		}
+
+		if projects.Pagination == nil || projects.NextPage == 0 {
+			break
+		}
+		options.PageNumber = projects.NextPage
 	}
 
-	return ws, nil
+	return "", fmt.Errorf("projeto %s não encontrado na organização %s", name, c.organization)
+}
+
+// newWorkspace converte um workspace do go-tfe para o formato usado pelo migrator
+func newWorkspace(ws *tfe.Workspace) Workspace {
+	workspace := Workspace{
+		ID:            ws.ID,
+		Name:          ws.Name,
+		Description:   ws.Description,
+		HasState:      ws.CurrentStateVersion != nil,
+		Tags:          ws.TagNames,
+		ResourceCount: ws.ResourceCount,
+		Locked:        ws.Locked,
+	}
+
+	if ws.CurrentStateVersion != nil {
+		workspace.CurrentStateVersion = ws.CurrentStateVersion.ID
+	}
+
+	if ws.Project != nil {
+		workspace.ProjectID = ws.Project.ID
+		workspace.ProjectName = ws.Project.Name
+	}
+
+	return workspace
+}
+
+// HasTags indica se o workspace possui todas as tags informadas
+func (w Workspace) HasTags(tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, wsTag := range w.Tags {
+			if wsTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 // ValidateConnection testa a conexão com o Terraform Cloud
 func (c *Client) ValidateConnection(ctx context.Context) error {
 	c.logger.Debug("Validando conexão com Terraform Cloud")
 
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
 	_, err := c.client.Organizations.Read(ctx, c.organization)
 	if err != nil {
-		return fmt.Errorf("erro ao validar conexão com Terraform Cloud: %w", err)
+		return fmt.Errorf("erro ao validar conexão com Terraform Cloud: %w", redact.Error(err))
 	}
 
 	c.logger.Info("Conexão com Terraform Cloud validada com sucesso")
 	return nil
-}
\ No newline at end of file
+}
+
+// parseRetryAfter interpreta o header Retry-After, em segundos ou como data HTTP
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
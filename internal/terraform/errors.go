@@ -0,0 +1,98 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+// ErrWorkspaceNotFound indica que o workspace não existe na organização. O Terraform Cloud também
+// responde 404 quando o token não tem acesso ao workspace.
+var ErrWorkspaceNotFound = errors.New("workspace não encontrado na organização (ou sem permissão de leitura)")
+
+// apiStatusPattern extrai o status HTTP das mensagens de erro do go-tfe, que usam o status
+// da resposta (ex: "500 Internal Server Error") quando o corpo não é um payload JSON:API
+var apiStatusPattern = regexp.MustCompile(`^(\d{3}) `)
+
+// APIError representa uma resposta de erro da API do Terraform Cloud com o status HTTP identificado
+type APIError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatusCode retorna o status HTTP da resposta, usado para classificar retentativas
+func (e *APIError) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// classifyAPIError envolve erros do go-tfe que carregam o status HTTP na mensagem em um APIError,
+// para que falhas transitórias (HTTP 429 e 5xx) sejam retentadas e falhas de autenticação identificadas
+func classifyAPIError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return err
+	}
+
+	// O go-tfe converte o HTTP 401 em um erro sem o status na mensagem
+	if errors.Is(err, tfe.ErrUnauthorized) {
+		return &APIError{StatusCode: http.StatusUnauthorized, Err: err}
+	}
+
+	matches := apiStatusPattern.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return err
+	}
+
+	status, convErr := strconv.Atoi(matches[1])
+	if convErr != nil {
+		return err
+	}
+
+	return &APIError{StatusCode: status, Err: err}
+}
+
+// refreshableURLError indica que a URL de download da versão atual estava ausente ou não respondeu
+// (HTTP 404), o que acontece enquanto o Terraform Cloud processa uma versão recém-criada.
+// É transitório porque cada nova tentativa lê a versão atual e obtém uma nova URL.
+type refreshableURLError struct {
+	Err error
+}
+
+func (e *refreshableURLError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *refreshableURLError) Unwrap() error {
+	return e.Err
+}
+
+// Transient permite que a abertura do estado seja retentada com backoff
+func (e *refreshableURLError) Transient() bool {
+	return true
+}
+
+// StateTooLargeError indica que o estado baixado ultrapassa migration.max_state_size_mb
+type StateTooLargeError struct {
+	WorkspaceName string
+	Limit         int64
+}
+
+func (e *StateTooLargeError) Error() string {
+	return fmt.Sprintf("estado do workspace %s ultrapassa o tamanho máximo de %d bytes (migration.max_state_size_mb)", e.WorkspaceName, e.Limit)
+}
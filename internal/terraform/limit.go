@@ -0,0 +1,45 @@
+package terraform
+
+import "io"
+
+// limitedBody interrompe a leitura do download com erro ao ultrapassar o limite,
+// em vez de truncar o estado silenciosamente como io.LimitReader
+type limitedBody struct {
+	body          io.ReadCloser
+	workspaceName string
+	remaining     int64
+	limit         int64
+}
+
+// newLimitedBody limita a leitura do corpo do download a limit bytes
+func newLimitedBody(body io.ReadCloser, workspaceName string, limit int64) io.ReadCloser {
+	return &limitedBody{
+		body:          body,
+		workspaceName: workspaceName,
+		remaining:     limit,
+		limit:         limit,
+	}
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, &StateTooLargeError{WorkspaceName: l.workspaceName, Limit: l.limit}
+	}
+
+	// Ler um byte além do limite para detectar estados maiores
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.body.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), &StateTooLargeError{WorkspaceName: l.workspaceName, Limit: l.limit}
+	}
+
+	return n, err
+}
+
+func (l *limitedBody) Close() error {
+	return l.body.Close()
+}
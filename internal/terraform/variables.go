@@ -0,0 +1,66 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-cloud-s3-migrator/internal/redact"
+
+	tfe "github.com/hashicorp/go-tfe"
+)
+
+// Variable representa uma variável de workspace do Terraform Cloud.
+// O valor de variáveis sensíveis nunca é retornado pela API e fica vazio.
+type Variable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value,omitempty"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category"` // terraform ou env
+	HCL         bool   `json:"hcl"`
+	Sensitive   bool   `json:"sensitive"`
+}
+
+// GetWorkspaceVariables lista as variáveis do workspace
+func (c *Client) GetWorkspaceVariables(ctx context.Context, workspaceID string) ([]Variable, error) {
+	options := &tfe.VariableListOptions{
+		ListOptions: tfe.ListOptions{
+			PageSize: 100,
+		},
+	}
+
+	var variables []Variable
+
+	for {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		list, err := c.client.Variables.List(ctx, workspaceID, options)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar variáveis do workspace %s: %w", workspaceID, redact.Error(err))
+		}
+
+		for _, v := range list.Items {
+			variable := Variable{
+				Key:         v.Key,
+				Value:       v.Value,
+				Description: v.Description,
+				Category:    string(v.Category),
+				HCL:         v.HCL,
+				Sensitive:   v.Sensitive,
+			}
+			// Garantir que nenhum valor sensível seja gravado, mesmo que a API o retorne
+			if variable.Sensitive {
+				variable.Value = ""
+			}
+			variables = append(variables, variable)
+		}
+
+		if list.Pagination == nil || list.NextPage == 0 {
+			break
+		}
+		options.PageNumber = list.NextPage
+	}
+
+	return variables, nil
+}